@@ -2,11 +2,15 @@ package charmer
 
 import (
 	"fmt"
+	"github.com/clambin/go-common/set"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"time"
 )
 
+// Arguments maps the name of each argument to its default value and help text. Supported types for Default are int,
+// float64, string, bool, time.Duration and set.Set of string, int or float64. As viper has no getter for a slice of float64,
+// read a set.Set[float64] with viper's UnmarshalKey and set.DecodeHook.
 type Arguments map[string]Argument
 
 type Argument struct {
@@ -28,6 +32,12 @@ func SetPersistentFlags(cmd *cobra.Command, v *viper.Viper, args Arguments) erro
 			cmd.PersistentFlags().Bool(name, val, arg.Help)
 		case time.Duration:
 			cmd.PersistentFlags().Duration(name, val, arg.Help)
+		case set.Set[string]:
+			setFlag(cmd, name, val, arg.Help)
+		case set.Set[int]:
+			setFlag(cmd, name, val, arg.Help)
+		case set.Set[float64]:
+			setFlag(cmd, name, val, arg.Help)
 		default:
 			return fmt.Errorf("unsupported type for flag '%s'", name)
 		}
@@ -36,13 +46,27 @@ func SetPersistentFlags(cmd *cobra.Command, v *viper.Viper, args Arguments) erro
 	return nil
 }
 
-// SetDefaults sets up default values in viper.
+// setFlag adds a flag for a set. The flag accepts a comma-separated list of values and can be repeated.
+func setFlag[T comparable](cmd *cobra.Command, name string, val set.Set[T], help string) {
+	s := val.Clone()
+	cmd.PersistentFlags().Var(set.NewFlagValue(&s), name, help)
+}
+
+// SetDefaults sets up default values in viper. Sets are stored as a sorted slice, so they can be read with e.g. viper's GetStringSlice.
 func SetDefaults(v *viper.Viper, args Arguments) error {
 	for name, arg := range args {
-		if arg.Default == nil {
+		switch val := arg.Default.(type) {
+		case nil:
 			return fmt.Errorf("no default for %s", name)
+		case set.Set[string]:
+			v.SetDefault(name, set.ListOrdered(val))
+		case set.Set[int]:
+			v.SetDefault(name, set.ListOrdered(val))
+		case set.Set[float64]:
+			v.SetDefault(name, set.ListOrdered(val))
+		default:
+			v.SetDefault(name, arg.Default)
 		}
-		v.SetDefault(name, arg.Default)
 	}
 	return nil
 }
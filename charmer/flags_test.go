@@ -2,9 +2,11 @@ package charmer_test
 
 import (
 	"github.com/clambin/go-common/charmer"
+	"github.com/clambin/go-common/set"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"math"
+	"reflect"
 	"testing"
 	"time"
 )
@@ -82,3 +84,52 @@ func TestSetDefaults_Invalid_Default(t *testing.T) {
 		t.Error("Expected error, got none")
 	}
 }
+
+func TestSetPersistentFlags_Set(t *testing.T) {
+	setArgs := charmer.Arguments{
+		"labels": {Default: set.New("b", "a")},
+		"ports":  {Default: set.New(80)},
+		"ratios": {Default: set.New(0.5)},
+	}
+
+	tests := []struct {
+		name   string
+		args   []string
+		labels []string
+		ports  []int
+		ratios set.Set[float64]
+	}{
+		{name: "default", labels: []string{"a", "b"}, ports: []int{80}, ratios: set.New(0.5)},
+		{name: "flags", args: []string{"--labels=c", "--labels=d,c", "--ports=443,80", "--ratios=1.5,0.25"}, labels: []string{"c", "d"}, ports: []int{80, 443}, ratios: set.New(0.25, 1.5)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cmd cobra.Command
+			v := viper.New()
+			if err := charmer.SetPersistentFlags(&cmd, v, setArgs); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err := charmer.SetDefaults(v, setArgs); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if err := cmd.PersistentFlags().Parse(tt.args); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if got := v.GetStringSlice("labels"); !reflect.DeepEqual(got, tt.labels) {
+				t.Errorf("labels: want %v, got %v", tt.labels, got)
+			}
+			if got := v.GetIntSlice("ports"); !reflect.DeepEqual(got, tt.ports) {
+				t.Errorf("ports: want %v, got %v", tt.ports, got)
+			}
+			// viper has no getter for float64 slices: use set.DecodeHook to decode the value
+			var ratios set.Set[float64]
+			if err := v.UnmarshalKey("ratios", &ratios, viper.DecodeHook(set.DecodeHook)); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !ratios.Equals(tt.ratios) {
+				t.Errorf("ratios: want %v, got %v", tt.ratios, ratios)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ module github.com/clambin/go-common/charmer
 go 1.24
 
 require (
+	github.com/clambin/go-common/set v0.0.0-00010101000000-000000000000
 	github.com/spf13/cobra v1.9.1
 	github.com/spf13/viper v1.19.0
 )
@@ -29,3 +30,5 @@ require (
 	gopkg.in/ini.v1 v1.67.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
+
+replace github.com/clambin/go-common/set => ../set
@@ -0,0 +1,154 @@
+package pubsub
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidTopic is returned when a topic, or a subscription pattern, is malformed.
+var ErrInvalidTopic = errors.New("invalid topic")
+
+// Broker routes messages to subscribers based on a topic.
+//
+// Topics consist of tokens, separated by a dot (e.g. "sensors.kitchen.temperature"). When subscribing,
+// a token may be replaced by a wildcard: "*" matches exactly one token, while ">" matches one or more tokens
+// and may only be used as the final token. E.g. "sensors.*.temperature" matches the temperature of all sensors,
+// while "sensors.>" matches all messages for all sensors.
+//
+// Each subscription pattern is served by its own Publisher. Once the last subscriber of a pattern unsubscribes,
+// the pattern is removed.
+type Broker[T any] struct {
+	topics  map[string]*topic[T]
+	clients map[<-chan T]string
+	lock    sync.RWMutex
+}
+
+// topic is the Publisher for a subscription pattern. subscribers counts the subscriptions to the pattern, including
+// those still being set up, so the Broker can remove the pattern without calling the Publisher while holding its lock.
+type topic[T any] struct {
+	publisher   *Publisher[T]
+	subscribers int
+}
+
+// Subscribe returns a channel that receives all messages published to topics matching the pattern.
+func (b *Broker[T]) Subscribe(pattern string) (<-chan T, error) {
+	if err := validateTopic(pattern, true); err != nil {
+		return nil, err
+	}
+	b.lock.Lock()
+	if b.topics == nil {
+		b.topics = make(map[string]*topic[T])
+		b.clients = make(map[<-chan T]string)
+	}
+	t, ok := b.topics[pattern]
+	if !ok {
+		t = &topic[T]{publisher: &Publisher[T]{}}
+		b.topics[pattern] = t
+	}
+	t.subscribers++
+	b.lock.Unlock()
+
+	// Subscribe waits for any ongoing Publish on the pattern, so don't hold the lock
+	ch, err := t.publisher.Subscribe()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if err != nil {
+		b.release(pattern, t)
+		return nil, err
+	}
+	b.clients[ch] = pattern
+	return ch, nil
+}
+
+// Unsubscribe removes the subscription and closes its channel. If the subscription was the last one for its pattern, the pattern is removed.
+func (b *Broker[T]) Unsubscribe(ch <-chan T) {
+	b.lock.Lock()
+	pattern, ok := b.clients[ch]
+	if !ok {
+		b.lock.Unlock()
+		return
+	}
+	delete(b.clients, ch)
+	t := b.topics[pattern]
+	b.release(pattern, t)
+	b.lock.Unlock()
+
+	// Unsubscribe waits for any ongoing Publish on the pattern, so don't hold the lock
+	t.publisher.Unsubscribe(ch)
+}
+
+// release removes a subscription from the topic, and removes the topic once it has no subscriptions left.
+// The caller must hold the lock.
+func (b *Broker[T]) release(pattern string, t *topic[T]) {
+	if t.subscribers--; t.subscribers == 0 && b.topics[pattern] == t {
+		delete(b.topics, pattern)
+	}
+}
+
+// Publish sends the message to all subscribers whose pattern matches the topic. Topic may not contain any wildcards.
+func (b *Broker[T]) Publish(topic string, msg T) error {
+	if err := validateTopic(topic, false); err != nil {
+		return err
+	}
+	tokens := strings.Split(topic, ".")
+	b.lock.RLock()
+	publishers := make([]*Publisher[T], 0, len(b.topics))
+	for pattern, t := range b.topics {
+		if match(strings.Split(pattern, "."), tokens) {
+			publishers = append(publishers, t.publisher)
+		}
+	}
+	b.lock.RUnlock()
+
+	var err error
+	for _, p := range publishers {
+		err = errors.Join(err, p.Publish(msg))
+	}
+	return err
+}
+
+// Topics returns the number of subscription patterns that currently have subscribers.
+func (b *Broker[T]) Topics() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return len(b.topics)
+}
+
+// Subscribers returns the total number of subscribers.
+func (b *Broker[T]) Subscribers() int {
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	return len(b.clients)
+}
+
+func validateTopic(topic string, wildcards bool) error {
+	tokens := strings.Split(topic, ".")
+	for i, token := range tokens {
+		switch {
+		case token == "":
+			return fmt.Errorf("%w: %q: empty token", ErrInvalidTopic, topic)
+		case !wildcards && (token == "*" || token == ">"):
+			return fmt.Errorf("%w: %q: wildcards not allowed", ErrInvalidTopic, topic)
+		case token == ">" && i != len(tokens)-1:
+			return fmt.Errorf("%w: %q: '>' must be the last token", ErrInvalidTopic, topic)
+		}
+	}
+	return nil
+}
+
+func match(pattern, topic []string) bool {
+	for i, token := range pattern {
+		switch {
+		case token == ">":
+			return len(topic) > i
+		case i >= len(topic):
+			return false
+		case token != "*" && token != topic[i]:
+			return false
+		}
+	}
+	return len(pattern) == len(topic)
+}
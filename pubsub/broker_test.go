@@ -0,0 +1,141 @@
+package pubsub_test
+
+import (
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBroker(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		topic   string
+		match   bool
+	}{
+		{name: "exact", pattern: "sensors.kitchen.temperature", topic: "sensors.kitchen.temperature", match: true},
+		{name: "exact mismatch", pattern: "sensors.kitchen.temperature", topic: "sensors.kitchen.humidity", match: false},
+		{name: "shorter topic", pattern: "sensors.kitchen.temperature", topic: "sensors.kitchen", match: false},
+		{name: "longer topic", pattern: "sensors.kitchen", topic: "sensors.kitchen.temperature", match: false},
+		{name: "single wildcard", pattern: "sensors.*.temperature", topic: "sensors.kitchen.temperature", match: true},
+		{name: "single wildcard mismatch", pattern: "sensors.*.temperature", topic: "sensors.kitchen.humidity", match: false},
+		{name: "single wildcard too deep", pattern: "sensors.*", topic: "sensors.kitchen.temperature", match: false},
+		{name: "tail wildcard", pattern: "sensors.>", topic: "sensors.kitchen.temperature", match: true},
+		{name: "tail wildcard needs a token", pattern: "sensors.>", topic: "sensors", match: false},
+		{name: "tail wildcard mismatch", pattern: "sensors.>", topic: "actuators.kitchen", match: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b pubsub.Broker[int]
+			ch, err := b.Subscribe(tt.pattern)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer b.Unsubscribe(ch)
+
+			// marker is a topic that always matches the pattern
+			marker := strings.NewReplacer("*", "x", ">", "x").Replace(tt.pattern)
+			go func() {
+				if err := b.Publish(tt.topic, 1); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if err := b.Publish(marker, 2); err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			}()
+
+			want := []int{2}
+			if tt.match {
+				want = []int{1, 2}
+			}
+			for _, w := range want {
+				if got := <-ch; got != w {
+					t.Errorf("got %d, want %d", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestBroker_Cleanup(t *testing.T) {
+	var b pubsub.Broker[int]
+	ch1, _ := b.Subscribe("a.>")
+	ch2, _ := b.Subscribe("a.>")
+	ch3, _ := b.Subscribe("a.b")
+
+	if got := b.Topics(); got != 2 {
+		t.Fatalf("got %d topics, want 2", got)
+	}
+	if got := b.Subscribers(); got != 3 {
+		t.Fatalf("got %d subscribers, want 3", got)
+	}
+
+	b.Unsubscribe(ch1)
+	if got := b.Topics(); got != 2 {
+		t.Errorf("got %d topics, want 2", got)
+	}
+	b.Unsubscribe(ch2)
+	if got := b.Topics(); got != 1 {
+		t.Errorf("got %d topics, want 1", got)
+	}
+	b.Unsubscribe(ch3)
+	b.Unsubscribe(ch3)
+	if got := b.Topics(); got != 0 {
+		t.Errorf("got %d topics, want 0", got)
+	}
+	if got := b.Subscribers(); got != 0 {
+		t.Errorf("got %d subscribers, want 0", got)
+	}
+}
+
+func TestBroker_InvalidTopic(t *testing.T) {
+	var b pubsub.Broker[int]
+	for _, pattern := range []string{"", "a..b", "a.>.b", ">.a"} {
+		if _, err := b.Subscribe(pattern); !errors.Is(err, pubsub.ErrInvalidTopic) {
+			t.Errorf("Subscribe(%q): got %v, want %v", pattern, err, pubsub.ErrInvalidTopic)
+		}
+	}
+	for _, topic := range []string{"", "a.*", "a.>"} {
+		if err := b.Publish(topic, 1); !errors.Is(err, pubsub.ErrInvalidTopic) {
+			t.Errorf("Publish(%q): got %v, want %v", topic, err, pubsub.ErrInvalidTopic)
+		}
+	}
+}
+
+func TestBroker_Unsubscribe_Blocked(t *testing.T) {
+	var b pubsub.Broker[int]
+	// stuck never reads, so publishing on "a" blocks
+	stuck, _ := b.Subscribe("a")
+	defer b.Unsubscribe(stuck)
+	ch1, _ := b.Subscribe("a")
+	ch2, _ := b.Subscribe("b")
+
+	go func() { _ = b.Publish("a", 1) }()
+	time.Sleep(100 * time.Millisecond)
+	// Unsubscribe waits for Publish on "a" to complete, but must not block the other patterns
+	go b.Unsubscribe(ch1)
+	time.Sleep(100 * time.Millisecond)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if err := b.Publish("b", 2); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+	select {
+	case got := <-ch2:
+		if got != 2 {
+			t.Errorf("got %d, want 2", got)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked by Unsubscribe")
+	}
+	<-done
+	if got := b.Subscribers(); got != 2 {
+		t.Errorf("got %d subscribers, want 2", got)
+	}
+}
@@ -0,0 +1,24 @@
+package pubsub
+
+import "encoding/json"
+
+// Codec encodes messages to, and decodes messages from, their binary representation.
+type Codec[T any] interface {
+	Encode(T) ([]byte, error)
+	Decode([]byte) (T, error)
+}
+
+var _ Codec[any] = JSONCodec[any]{}
+
+// JSONCodec encodes messages as JSON.
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Encode(msg T) ([]byte, error) {
+	return json.Marshal(msg)
+}
+
+func (JSONCodec[T]) Decode(data []byte) (T, error) {
+	var msg T
+	err := json.Unmarshal(data, &msg)
+	return msg, err
+}
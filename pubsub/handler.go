@@ -0,0 +1,110 @@
+package pubsub
+
+import (
+	"context"
+	"fmt"
+	"hash/maphash"
+	"sync"
+)
+
+// HandleOptions contains configuration options for Publisher.Handle.
+type HandleOptions[T any] struct {
+	// Workers is the number of goroutines handling messages. Defaults to 1.
+	Workers int
+	// Key returns the key of a message. If set, messages with the same key are handled by the same worker,
+	// in the order they were published. If nil, messages are handled by the first available worker.
+	Key func(T) string
+	// OnError is called when the handler returns an error or panics. If nil, errors are ignored.
+	OnError func(T, error)
+}
+
+// Subscription is returned by Publisher.Handle and stops the handler.
+type Subscription struct {
+	stop   func()
+	done   chan struct{}
+	cancel context.CancelFunc
+}
+
+// Handle subscribes to the Publisher and calls handler for each received message, using one or more workers.
+// A panic in the handler is recovered and reported to OnError, like any other error.
+//
+// Handle returns a Subscription that stops the handler. The context passed to the handler is cancelled when
+// Stop gives up waiting for the in-flight messages to be handled.
+func (p *Publisher[T]) Handle(handler func(context.Context, T) error, options HandleOptions[T]) (*Subscription, error) {
+	ch, err := p.Subscribe()
+	if err != nil {
+		return nil, err
+	}
+	workers := max(options.Workers, 1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// without a key, all workers share a single queue
+	queues := make([]chan T, 1, workers)
+	queues[0] = make(chan T)
+	if options.Key != nil {
+		for range workers - 1 {
+			queues = append(queues, make(chan T))
+		}
+	}
+
+	var wg sync.WaitGroup
+	for i := range workers {
+		wg.Add(1)
+		go func(queue <-chan T) {
+			defer wg.Done()
+			for msg := range queue {
+				if err := handle(ctx, handler, msg); err != nil && options.OnError != nil {
+					options.OnError(msg, err)
+				}
+			}
+		}(queues[i%len(queues)])
+	}
+
+	go func() {
+		seed := maphash.MakeSeed()
+		for msg := range ch {
+			queue := queues[0]
+			if options.Key != nil {
+				queue = queues[maphash.String(seed, options.Key(msg))%uint64(len(queues))]
+			}
+			queue <- msg
+		}
+		for _, queue := range queues {
+			close(queue)
+		}
+	}()
+
+	s := Subscription{
+		stop:   sync.OnceFunc(func() { p.Unsubscribe(ch) }),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go func() {
+		wg.Wait()
+		cancel()
+		close(s.done)
+	}()
+	return &s, nil
+}
+
+// Stop unsubscribes from the Publisher and waits for all in-flight messages to be handled. If the context expires
+// first, the handler's context is cancelled and Stop returns the context's error.
+func (s *Subscription) Stop(ctx context.Context) error {
+	s.stop()
+	select {
+	case <-s.done:
+		return nil
+	case <-ctx.Done():
+		s.cancel()
+		return ctx.Err()
+	}
+}
+
+func handle[T any](ctx context.Context, handler func(context.Context, T) error, msg T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, msg)
+}
@@ -0,0 +1,102 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPublisher_Handle(t *testing.T) {
+	var p pubsub.Publisher[int]
+
+	var lock sync.Mutex
+	received := make(map[string][]int)
+	var errs []error
+	s, err := p.Handle(func(_ context.Context, i int) error {
+		switch i {
+		case 10:
+			return errors.New("failed")
+		case 20:
+			panic("boom")
+		}
+		lock.Lock()
+		defer lock.Unlock()
+		key := strconv.Itoa(i % 3)
+		received[key] = append(received[key], i)
+		return nil
+	}, pubsub.HandleOptions[int]{
+		Workers: 4,
+		Key:     func(i int) string { return strconv.Itoa(i % 3) },
+		OnError: func(_ int, err error) {
+			lock.Lock()
+			defer lock.Unlock()
+			errs = append(errs, err)
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const count = 100
+	for i := range count {
+		_ = p.Publish(i)
+	}
+	if err = s.Stop(t.Context()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d subscribers, want 0", got)
+	}
+
+	var total int
+	for key, values := range received {
+		for i := 1; i < len(values); i++ {
+			if values[i] < values[i-1] {
+				t.Errorf("key %s: messages out of order: %v", key, values)
+				break
+			}
+		}
+		total += len(values)
+	}
+	if total != count-2 {
+		t.Errorf("got %d messages, want %d", total, count-2)
+	}
+	if len(errs) != 2 {
+		t.Errorf("got %d errors, want 2", len(errs))
+	}
+}
+
+func TestSubscription_Stop_Timeout(t *testing.T) {
+	var p pubsub.Publisher[int]
+	handled := make(chan struct{})
+	s, _ := p.Handle(func(ctx context.Context, _ int) error {
+		close(handled)
+		<-ctx.Done()
+		return ctx.Err()
+	}, pubsub.HandleOptions[int]{})
+
+	_ = p.Publish(1)
+	<-handled
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got %v, want %v", err, context.DeadlineExceeded)
+	}
+	// the handler has been cancelled, so a second Stop completes
+	if err := s.Stop(t.Context()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestPublisher_Handle_Closed(t *testing.T) {
+	var p pubsub.Publisher[int]
+	p.Close()
+	if _, err := p.Handle(func(context.Context, int) error { return nil }, pubsub.HandleOptions[int]{}); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("got %v, want %v", err, pubsub.ErrClosed)
+	}
+}
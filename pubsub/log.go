@@ -0,0 +1,376 @@
+package pubsub
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCorrupt is returned when the log contains a record that can't be read.
+var ErrCorrupt = errors.New("corrupt record")
+
+const (
+	segmentSuffix   = ".log"
+	consumersDir    = "consumers"
+	recordHeaderLen = 8
+)
+
+// LogOptions contains configuration options for a Log.
+type LogOptions[T any] struct {
+	// Codec encodes the messages written to the log. If nil, messages are encoded as JSON.
+	Codec Codec[T]
+	// SegmentSize is the size (in bytes) after which the log starts a new segment. Defaults to 16 MiB.
+	SegmentSize int64
+	// MaxSegments is the number of segments to keep. Older segments are removed. If zero, segments are kept indefinitely.
+	MaxSegments int
+	// MaxAge is the time after which a segment is removed, counted from its last write. The active segment is never removed.
+	// If zero, segments are kept indefinitely.
+	MaxAge time.Duration
+	// Sync flushes each message to disk before Append returns.
+	Sync bool
+}
+
+// Log is a file-backed, append-only log of messages. Each message is identified by its offset in the log.
+// The log is stored in a directory as a sequence of segments. When a segment reaches its maximum size, the log
+// starts a new segment, and removes older segments as determined by the retention options.
+//
+// Pass a Log to NewPublisher to write each published message to the log. Consumers read the log and keep track
+// of the messages they have processed, so they can resume where they left off after a restart.
+type Log[T any] struct {
+	dir      string
+	options  LogOptions[T]
+	lock     sync.RWMutex
+	segments []uint64
+	active   *os.File
+	size     int64
+	next     uint64
+}
+
+// OpenLog opens the log in the provided directory, creating it if needed. Any incomplete record at the end of the log
+// (e.g. after a crash) is removed.
+func OpenLog[T any](dir string, options LogOptions[T]) (*Log[T], error) {
+	if options.Codec == nil {
+		options.Codec = JSONCodec[T]{}
+	}
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = 16 << 20
+	}
+	if err := os.MkdirAll(filepath.Join(dir, consumersDir), 0o755); err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	l := Log[T]{dir: dir, options: options}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	for _, entry := range entries {
+		if base, ok := strings.CutSuffix(entry.Name(), segmentSuffix); ok && entry.Type().IsRegular() {
+			if offset, err := strconv.ParseUint(base, 10, 64); err == nil {
+				l.segments = append(l.segments, offset)
+			}
+		}
+	}
+	slices.Sort(l.segments)
+	if len(l.segments) == 0 {
+		l.segments = []uint64{0}
+	}
+
+	last := l.segments[len(l.segments)-1]
+	count, size, err := recoverSegment(l.segmentPath(last))
+	if err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	if l.active, err = os.OpenFile(l.segmentPath(last), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644); err != nil {
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	l.size = size
+	l.next = last + count
+	if err = l.enforceRetention(); err != nil {
+		_ = l.active.Close()
+		return nil, fmt.Errorf("log: %w", err)
+	}
+	return &l, nil
+}
+
+// Append writes the message to the log and returns its offset.
+func (l *Log[T]) Append(msg T) (uint64, error) {
+	data, err := l.options.Codec.Encode(msg)
+	if err != nil {
+		return 0, fmt.Errorf("encode: %w", err)
+	}
+	if uint64(len(data)) > math.MaxUint32 {
+		return 0, fmt.Errorf("encode: message too large: %d bytes", len(data))
+	}
+	record := makeRecord(data)
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.active == nil {
+		return 0, os.ErrClosed
+	}
+	if l.size > 0 && l.size+int64(len(record)) > l.options.SegmentSize {
+		if err = l.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	if _, err = l.active.Write(record); err == nil && l.options.Sync {
+		err = l.active.Sync()
+	}
+	if err != nil {
+		return 0, err
+	}
+	l.size += int64(len(record))
+	offset := l.next
+	l.next++
+	return offset, nil
+}
+
+// Read calls fn for each message in the log, starting at offset from, until it reaches the end of the log
+// or fn returns an error. If the message at offset from was removed, Read starts at the oldest available message.
+// Messages appended while Read is running are not read.
+func (l *Log[T]) Read(from uint64, fn func(offset uint64, msg T) error) error {
+	l.lock.RLock()
+	segments := slices.Clone(l.segments)
+	end := l.next
+	l.lock.RUnlock()
+
+	for i, base := range segments {
+		segmentEnd := end
+		if i < len(segments)-1 {
+			segmentEnd = segments[i+1]
+		}
+		if segmentEnd <= from {
+			continue
+		}
+		if err := l.readSegment(base, segmentEnd, from, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Log[T]) readSegment(base, end, from uint64, fn func(uint64, T) error) error {
+	f, err := os.Open(l.segmentPath(base))
+	if errors.Is(err, os.ErrNotExist) {
+		// segment was removed by retention
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(f)
+	for offset := base; offset < end; offset++ {
+		data, err := readRecord(r, info.Size())
+		if err != nil {
+			return fmt.Errorf("offset %d: %w", offset, err)
+		}
+		if offset < from {
+			continue
+		}
+		msg, err := l.options.Codec.Decode(data)
+		if err != nil {
+			return fmt.Errorf("offset %d: decode: %w", offset, err)
+		}
+		if err = fn(offset, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// First returns the offset of the oldest message in the log.
+func (l *Log[T]) First() uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.segments[0]
+}
+
+// Next returns the offset that the next appended message will receive.
+func (l *Log[T]) Next() uint64 {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+	return l.next
+}
+
+// Close closes the log. Once closed, Append returns os.ErrClosed.
+func (l *Log[T]) Close() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	if l.active == nil {
+		return nil
+	}
+	err := l.active.Close()
+	l.active = nil
+	return err
+}
+
+func (l *Log[T]) rotate() error {
+	f, err := os.OpenFile(l.segmentPath(l.next), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	_ = l.active.Close()
+	l.active = f
+	l.size = 0
+	l.segments = append(l.segments, l.next)
+	return l.enforceRetention()
+}
+
+func (l *Log[T]) enforceRetention() error {
+	for len(l.segments) > 1 {
+		expired := l.options.MaxSegments > 0 && len(l.segments) > l.options.MaxSegments
+		if !expired && l.options.MaxAge > 0 {
+			info, err := os.Stat(l.segmentPath(l.segments[0]))
+			if err != nil {
+				return err
+			}
+			expired = time.Since(info.ModTime()) > l.options.MaxAge
+		}
+		if !expired {
+			break
+		}
+		if err := os.Remove(l.segmentPath(l.segments[0])); err != nil {
+			return err
+		}
+		l.segments = l.segments[1:]
+	}
+	return nil
+}
+
+func (l *Log[T]) segmentPath(base uint64) string {
+	return filepath.Join(l.dir, fmt.Sprintf("%020d%s", base, segmentSuffix))
+}
+
+// recoverSegment returns the number of valid records in a segment and their total size, and truncates any incomplete
+// or corrupt records at the end of the segment.
+func recoverSegment(path string) (uint64, int64, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var count uint64
+	var size int64
+	r := bufio.NewReader(f)
+	for {
+		// a record can't be larger than the segment: a larger size means the record is incomplete
+		data, err := readRecord(r, info.Size())
+		if err != nil {
+			break
+		}
+		count++
+		size += int64(recordHeaderLen + len(data))
+	}
+	return count, size, f.Truncate(size)
+}
+
+// makeRecord prefixes the data with its length and a checksum. The checksum covers both the length and the data,
+// so a header of zeroes (e.g. a zero-filled tail after a crash) is not a valid record.
+func makeRecord(data []byte) []byte {
+	record := make([]byte, recordHeaderLen+len(data))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(data)))
+	copy(record[recordHeaderLen:], data)
+	binary.BigEndian.PutUint32(record[4:8], checksum(record[0:4], data))
+	return record
+}
+
+// readRecord reads a record written by makeRecord. Records larger than maxSize are corrupt: the length is checked
+// before the record is read, so a corrupt length doesn't cause a large allocation.
+func readRecord(r io.Reader, maxSize int64) ([]byte, error) {
+	var header [recordHeaderLen]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[0:4])
+	if int64(size) > maxSize {
+		return nil, fmt.Errorf("%w: invalid size: %d bytes", ErrCorrupt, size)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	if checksum(header[0:4], data) != binary.BigEndian.Uint32(header[4:8]) {
+		return nil, ErrCorrupt
+	}
+	return data, nil
+}
+
+func checksum(size, data []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE(size), crc32.IEEETable, data)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Consumer reads messages from a Log and keeps track of the last message it acknowledged. A Consumer
+// with the same name resumes after the last acknowledged message. A Consumer is not safe for concurrent use.
+type Consumer[T any] struct {
+	log  *Log[T]
+	path string
+	next uint64
+}
+
+// Consumer returns the named consumer, positioned after its last acknowledged message.
+func (l *Log[T]) Consumer(name string) (*Consumer[T], error) {
+	if name == "" || name == "." || name == ".." || filepath.Base(name) != name {
+		return nil, fmt.Errorf("invalid consumer name: %q", name)
+	}
+	c := Consumer[T]{log: l, path: filepath.Join(l.dir, consumersDir, name)}
+	data, err := os.ReadFile(c.path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		if c.next, err = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64); err != nil {
+			return nil, fmt.Errorf("consumer %s: invalid offset: %w", name, err)
+		}
+	}
+	return &c, nil
+}
+
+// Read calls fn for each message that the consumer hasn't read yet, up to the end of the log.
+// If fn returns an error, Read stops and the message is read again on the next call to Read.
+func (c *Consumer[T]) Read(fn func(offset uint64, msg T) error) error {
+	return c.log.Read(c.next, func(offset uint64, msg T) error {
+		if err := fn(offset, msg); err != nil {
+			return err
+		}
+		c.next = offset + 1
+		return nil
+	})
+}
+
+// Ack records that all messages up to, and including, offset have been processed.
+func (c *Consumer[T]) Ack(offset uint64) error {
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(strconv.FormatUint(offset+1, 10)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path)
+}
+
+// Offset returns the offset of the next message the consumer will read.
+func (c *Consumer[T]) Offset() uint64 {
+	return c.next
+}
@@ -0,0 +1,238 @@
+package pubsub_test
+
+import (
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLog(t *testing.T) {
+	dir := t.TempDir()
+	l, err := pubsub.OpenLog(dir, pubsub.LogOptions[int]{SegmentSize: 64})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range 20 {
+		if offset, err := l.Append(i); err != nil || offset != uint64(i) {
+			t.Fatalf("Append(%d): got (%d, %v)", i, offset, err)
+		}
+	}
+	if err = l.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err = l.Append(20); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("got %v, want %v", err, os.ErrClosed)
+	}
+
+	// the log was rotated
+	segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+	if len(segments) < 2 {
+		t.Errorf("got %d segments, want at least 2", len(segments))
+	}
+
+	// simulate a crash during a write
+	last := segments[len(segments)-1]
+	f, _ := os.OpenFile(last, os.O_WRONLY|os.O_APPEND, 0o644)
+	_, _ = f.Write([]byte{0, 0, 0, 10, 1, 2})
+	_ = f.Close()
+
+	// reopening the log drops the incomplete record and continues at the next offset
+	if l, err = pubsub.OpenLog(dir, pubsub.LogOptions[int]{SegmentSize: 64}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+	if offset, err := l.Append(20); err != nil || offset != 20 {
+		t.Fatalf("Append(20): got (%d, %v)", offset, err)
+	}
+
+	var got []int
+	if err = l.Read(15, func(offset uint64, msg int) error {
+		if uint64(msg) != offset {
+			t.Errorf("offset %d: got %d", offset, msg)
+		}
+		got = append(got, msg)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := []int{15, 16, 17, 18, 19, 20}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestLog_Recover(t *testing.T) {
+	tests := []struct {
+		name string
+		tail []byte
+	}{
+		{name: "incomplete record", tail: []byte{0, 0, 0, 10, 1, 2}},
+		{name: "zero-filled", tail: make([]byte, 16)},
+		{name: "too large", tail: []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			l, err := pubsub.OpenLog(dir, pubsub.LogOptions[int]{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for i := range 2 {
+				_, _ = l.Append(i)
+			}
+			_ = l.Close()
+
+			// simulate a crash during a write
+			segments, _ := filepath.Glob(filepath.Join(dir, "*.log"))
+			f, _ := os.OpenFile(segments[0], os.O_WRONLY|os.O_APPEND, 0o644)
+			_, _ = f.Write(tt.tail)
+			_ = f.Close()
+
+			if l, err = pubsub.OpenLog(dir, pubsub.LogOptions[int]{}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			defer func() { _ = l.Close() }()
+			if offset, err := l.Append(2); err != nil || offset != 2 {
+				t.Fatalf("Append(2): got (%d, %v)", offset, err)
+			}
+			var got []int
+			if err = l.Read(0, func(_ uint64, msg int) error {
+				got = append(got, msg)
+				return nil
+			}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := []int{0, 1, 2}; !slices.Equal(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestLog_Reopen(t *testing.T) {
+	dir := t.TempDir()
+	l, err := pubsub.OpenLog(dir, pubsub.LogOptions[string]{Codec: rawCodec{}, SegmentSize: 1000})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// a record larger than the new segment size, and an empty record
+	want := []string{strings.Repeat("a", 100), "", "b"}
+	for _, msg := range want {
+		if _, err = l.Append(msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_ = l.Close()
+
+	// changing the segment size doesn't affect the existing records
+	if l, err = pubsub.OpenLog(dir, pubsub.LogOptions[string]{Codec: rawCodec{}, SegmentSize: 50}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+	if got := l.Next(); got != 3 {
+		t.Errorf("got %d, want 3", got)
+	}
+	var got []string
+	if err = l.Read(0, func(_ uint64, msg string) error {
+		got = append(got, msg)
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+// rawCodec encodes a string as is, so an empty string results in an empty record.
+type rawCodec struct{}
+
+func (rawCodec) Encode(msg string) ([]byte, error)  { return []byte(msg), nil }
+func (rawCodec) Decode(data []byte) (string, error) { return string(data), nil }
+
+func TestLog_Retention(t *testing.T) {
+	l, err := pubsub.OpenLog(t.TempDir(), pubsub.LogOptions[int]{SegmentSize: 10, MaxSegments: 3})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = l.Close() }()
+
+	// each message gets its own segment
+	for i := range 10 {
+		_, _ = l.Append(i)
+	}
+	if got := l.First(); got != 7 {
+		t.Errorf("got first offset %d, want 7", got)
+	}
+	if got := l.Next(); got != 10 {
+		t.Errorf("got next offset %d, want 10", got)
+	}
+
+	var got []int
+	_ = l.Read(0, func(_ uint64, msg int) error {
+		got = append(got, msg)
+		return nil
+	})
+	if want := []int{7, 8, 9}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestConsumer(t *testing.T) {
+	dir := t.TempDir()
+	l, _ := pubsub.OpenLog(dir, pubsub.LogOptions[string]{})
+	defer func() { _ = l.Close() }()
+
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[string]{Log: l})
+	for _, msg := range []string{"a", "b", "c", "d"} {
+		if err := p.Publish(msg); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	c, err := l.Consumer("worker")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	errStop := errors.New("stop")
+	var got []string
+	err = c.Read(func(offset uint64, msg string) error {
+		if msg == "c" {
+			return errStop
+		}
+		got = append(got, msg)
+		return c.Ack(offset)
+	})
+	if !errors.Is(err, errStop) {
+		t.Fatalf("got %v, want %v", err, errStop)
+	}
+	if c.Offset() != 2 {
+		t.Errorf("got offset %d, want 2", c.Offset())
+	}
+
+	// a new consumer with the same name resumes after the last acknowledged message
+	if c, err = l.Consumer("worker"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_ = c.Read(func(_ uint64, msg string) error {
+		got = append(got, msg)
+		return nil
+	})
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err = l.Consumer("../worker"); err == nil {
+		t.Error("expected error for invalid consumer name")
+	}
+
+	// a publisher doesn't deliver messages it failed to store
+	_ = l.Close()
+	if err = p.Publish("e"); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("got %v, want %v", err, os.ErrClosed)
+	}
+}
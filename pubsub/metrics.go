@@ -0,0 +1,57 @@
+package pubsub
+
+import "time"
+
+// Metrics measures the behaviour of a Publisher. Pass it to NewPublisher through PublisherOptions.
+// The github.com/clambin/go-common/pubsub/metrics module provides an implementation that exports Prometheus metrics.
+//
+// A Publisher calls Metrics from all goroutines calling its methods, so implementations must be safe for concurrent use.
+type Metrics interface {
+	// Subscribed is called with 1 when a subscriber is added, and with -1 when a subscriber is removed.
+	Subscribed(delta int)
+	// Published is called when Publish has successfully delivered a message, with the duration of the call.
+	Published(duration time.Duration)
+	// Delivered is called when a message is delivered to a subscriber.
+	Delivered()
+	// Dropped is called when a message is not delivered to a subscriber, with the reason why.
+	Dropped(reason string)
+}
+
+// Reasons why a message was not delivered to a subscriber, as passed to Metrics.Dropped.
+const (
+	// DropFiltered indicates the subscriber's filter rejected the message.
+	DropFiltered = "filtered"
+	// DropUnsubscribed indicates the subscriber unsubscribed while the message was being delivered.
+	DropUnsubscribed = "unsubscribed"
+	// DropEvicted indicates the subscriber was evicted because it was too slow to receive the message.
+	DropEvicted = "evicted"
+)
+
+// recorder passes measurements to Metrics. If no Metrics are configured, it does nothing.
+type recorder struct {
+	metrics Metrics
+}
+
+func (r recorder) subscribed(delta int) {
+	if r.metrics != nil {
+		r.metrics.Subscribed(delta)
+	}
+}
+
+func (r recorder) deliver() {
+	if r.metrics != nil {
+		r.metrics.Delivered()
+	}
+}
+
+func (r recorder) drop(reason string) {
+	if r.metrics != nil {
+		r.metrics.Dropped(reason)
+	}
+}
+
+func (r recorder) publish(start time.Time) {
+	if r.metrics != nil {
+		r.metrics.Published(time.Since(start))
+	}
+}
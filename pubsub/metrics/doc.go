@@ -0,0 +1,5 @@
+// Package metrics provides Prometheus metrics for a pubsub.Publisher. It is a separate module, so that pubsub
+// doesn't depend on the Prometheus client library.
+//
+// Deprecated: moved to codeberg.org/clambin/go-common
+package metrics
@@ -0,0 +1,109 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"time"
+)
+
+// Options provides configuration options for NewPublisherMetrics
+type Options struct {
+	// Namespace to prepend to the metric name
+	Namespace string
+	// Subsystem to prepend to the metric name
+	Subsystem string
+	// ConstLabels to add to the metric
+	ConstLabels prometheus.Labels
+	// Buckets for the publish duration histogram. If empty, prometheus.DefBuckets is used.
+	Buckets []float64
+}
+
+// PublisherMetrics measures the behaviour of a Publisher. It implements pubsub.Metrics: pass it to pubsub.NewPublisher
+// through pubsub.PublisherOptions. The caller must register PublisherMetrics with a Prometheus registry.
+type PublisherMetrics struct {
+	subscribers prometheus.Gauge
+	published   prometheus.Counter
+	delivered   prometheus.Counter
+	dropped     *prometheus.CounterVec
+	duration    prometheus.Histogram
+}
+
+var _ prometheus.Collector = &PublisherMetrics{}
+
+// NewPublisherMetrics returns PublisherMetrics that measure the number of subscribers, the number of published,
+// delivered and dropped messages, and the duration of Publish calls.
+func NewPublisherMetrics(options Options) *PublisherMetrics {
+	if len(options.Buckets) == 0 {
+		options.Buckets = prometheus.DefBuckets
+	}
+	return &PublisherMetrics{
+		subscribers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "pubsub_subscribers",
+			Help:        "number of subscribers",
+			ConstLabels: options.ConstLabels,
+		}),
+		published: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "pubsub_messages_published_total",
+			Help:        "total number of published messages",
+			ConstLabels: options.ConstLabels,
+		}),
+		delivered: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "pubsub_messages_delivered_total",
+			Help:        "total number of messages delivered to subscribers",
+			ConstLabels: options.ConstLabels,
+		}),
+		dropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "pubsub_messages_dropped_total",
+			Help:        "total number of messages not delivered to subscribers",
+			ConstLabels: options.ConstLabels,
+		}, []string{"reason"}),
+		duration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace:   options.Namespace,
+			Subsystem:   options.Subsystem,
+			Name:        "pubsub_publish_duration_seconds",
+			Help:        "duration of publish calls",
+			ConstLabels: options.ConstLabels,
+			Buckets:     options.Buckets,
+		}),
+	}
+}
+
+func (m *PublisherMetrics) Subscribed(delta int) {
+	m.subscribers.Add(float64(delta))
+}
+
+func (m *PublisherMetrics) Published(duration time.Duration) {
+	m.published.Inc()
+	m.duration.Observe(duration.Seconds())
+}
+
+func (m *PublisherMetrics) Delivered() {
+	m.delivered.Inc()
+}
+
+func (m *PublisherMetrics) Dropped(reason string) {
+	m.dropped.WithLabelValues(reason).Inc()
+}
+
+func (m *PublisherMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.subscribers.Describe(ch)
+	m.published.Describe(ch)
+	m.delivered.Describe(ch)
+	m.dropped.Describe(ch)
+	m.duration.Describe(ch)
+}
+
+func (m *PublisherMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.subscribers.Collect(ch)
+	m.published.Collect(ch)
+	m.delivered.Collect(ch)
+	m.dropped.Collect(ch)
+	m.duration.Collect(ch)
+}
@@ -0,0 +1,52 @@
+package metrics_test
+
+import (
+	"github.com/clambin/go-common/pubsub/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPublisherMetrics(t *testing.T) {
+	m := metrics.NewPublisherMetrics(metrics.Options{
+		Namespace:   "foo",
+		Subsystem:   "bar",
+		ConstLabels: prometheus.Labels{"application": "app"},
+	})
+
+	m.Subscribed(1)
+	m.Subscribed(1)
+	m.Subscribed(-1)
+	m.Published(time.Millisecond)
+	m.Published(time.Millisecond)
+	m.Delivered()
+	m.Dropped("filtered")
+
+	const want = `
+# HELP foo_bar_pubsub_messages_delivered_total total number of messages delivered to subscribers
+# TYPE foo_bar_pubsub_messages_delivered_total counter
+foo_bar_pubsub_messages_delivered_total{application="app"} 1
+# HELP foo_bar_pubsub_messages_dropped_total total number of messages not delivered to subscribers
+# TYPE foo_bar_pubsub_messages_dropped_total counter
+foo_bar_pubsub_messages_dropped_total{application="app",reason="filtered"} 1
+# HELP foo_bar_pubsub_messages_published_total total number of published messages
+# TYPE foo_bar_pubsub_messages_published_total counter
+foo_bar_pubsub_messages_published_total{application="app"} 2
+# HELP foo_bar_pubsub_subscribers number of subscribers
+# TYPE foo_bar_pubsub_subscribers gauge
+foo_bar_pubsub_subscribers{application="app"} 1
+`
+	if err := testutil.CollectAndCompare(m, strings.NewReader(want),
+		"foo_bar_pubsub_messages_delivered_total",
+		"foo_bar_pubsub_messages_dropped_total",
+		"foo_bar_pubsub_messages_published_total",
+		"foo_bar_pubsub_subscribers",
+	); err != nil {
+		t.Error(err)
+	}
+	if got := testutil.CollectAndCount(m, "foo_bar_pubsub_publish_duration_seconds"); got != 1 {
+		t.Errorf("got %d, want 1", got)
+	}
+}
@@ -0,0 +1,95 @@
+package pubsub_test
+
+import (
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"maps"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMetrics(t *testing.T) {
+	var m fakeMetrics
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{Metrics: &m})
+
+	even, _ := p.SubscribeFunc(func(i int) bool { return i%2 == 0 })
+	all, _ := p.Subscribe()
+	idle, _ := p.Subscribe()
+	p.Unsubscribe(idle)
+	go func() {
+		for range all {
+		}
+	}()
+	go func() {
+		for range even {
+		}
+	}()
+
+	for i := range 4 {
+		if err := p.Publish(i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	m.check(t, 2, 4, 6, map[string]int{pubsub.DropFiltered: 2})
+
+	// failed calls to Publish are not measured
+	p.Close()
+	if err := p.Publish(4); !errors.Is(err, pubsub.ErrClosed) {
+		t.Fatalf("got %v, want %v", err, pubsub.ErrClosed)
+	}
+	m.check(t, 0, 4, 6, map[string]int{pubsub.DropFiltered: 2})
+}
+
+type fakeMetrics struct {
+	subscribers int
+	published   int
+	delivered   int
+	dropped     map[string]int
+	lock        sync.Mutex
+}
+
+func (f *fakeMetrics) Subscribed(delta int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.subscribers += delta
+}
+
+func (f *fakeMetrics) Published(time.Duration) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.published++
+}
+
+func (f *fakeMetrics) Delivered() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.delivered++
+}
+
+func (f *fakeMetrics) Dropped(reason string) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.dropped == nil {
+		f.dropped = make(map[string]int)
+	}
+	f.dropped[reason]++
+}
+
+func (f *fakeMetrics) check(t *testing.T, subscribers, published, delivered int, dropped map[string]int) {
+	t.Helper()
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.subscribers != subscribers {
+		t.Errorf("subscribers: got %d, want %d", f.subscribers, subscribers)
+	}
+	if f.published != published {
+		t.Errorf("published: got %d, want %d", f.published, published)
+	}
+	if f.delivered != delivered {
+		t.Errorf("delivered: got %d, want %d", f.delivered, delivered)
+	}
+	if !maps.Equal(f.dropped, dropped) {
+		t.Errorf("dropped: got %v, want %v", f.dropped, dropped)
+	}
+}
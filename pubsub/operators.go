@@ -0,0 +1,184 @@
+package pubsub
+
+import (
+	"context"
+	"time"
+)
+
+// The operators below read messages from a channel (typically a subscription) and write the result to a new channel.
+// The new channel is closed when the input channel is closed or the context is cancelled, at which point the operator's
+// goroutine stops. Operators don't unsubscribe from the Publisher: use SubscribeContext with the same context to do so.
+
+// Map returns a channel that receives the result of f for each message.
+func Map[T, U any](ctx context.Context, in <-chan T, f func(T) U) <-chan U {
+	out := make(chan U)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok || !send(ctx, out, f(msg)) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Filter returns a channel that only receives the messages for which f returns true.
+func Filter[T any](ctx context.Context, in <-chan T, f func(T) bool) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok || f(msg) && !send(ctx, out, msg) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Debounce returns a channel that receives a message once no new messages were received for the duration d.
+// Only the latest message is sent. When the input channel is closed, any pending message is sent first.
+func Debounce[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan T {
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(d)
+		timer.Stop()
+		defer timer.Stop()
+		var latest T
+		var pending bool
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					if pending {
+						send(ctx, out, latest)
+					}
+					return
+				}
+				latest, pending = msg, true
+				timer.Reset(d)
+			case <-timer.C:
+				if !send(ctx, out, latest) {
+					return
+				}
+				pending = false
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Throttle returns a channel that receives at most one message per duration d. The first message is sent immediately.
+// Messages received while throttled are dropped, except for the latest one, which is sent when the duration expires.
+// If d is not positive, all messages are passed through.
+func Throttle[T any](ctx context.Context, in <-chan T, d time.Duration) <-chan T {
+	if d <= 0 {
+		return Map(ctx, in, func(msg T) T { return msg })
+	}
+	out := make(chan T)
+	go func() {
+		defer close(out)
+		ticker := time.NewTicker(d)
+		defer ticker.Stop()
+		var latest T
+		var pending bool
+		throttled := false
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					if pending {
+						send(ctx, out, latest)
+					}
+					return
+				}
+				if throttled {
+					latest, pending = msg, true
+					continue
+				}
+				if !send(ctx, out, msg) {
+					return
+				}
+				throttled = true
+				ticker.Reset(d)
+			case <-ticker.C:
+				if throttled = pending; pending {
+					if !send(ctx, out, latest) {
+						return
+					}
+					pending = false
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// Batch returns a channel that receives messages in batches of up to size messages. A partial batch is sent
+// when maxWait has passed since its first message was received, or when the input channel is closed.
+func Batch[T any](ctx context.Context, in <-chan T, size int, maxWait time.Duration) <-chan []T {
+	size = max(size, 1)
+	out := make(chan []T)
+	go func() {
+		defer close(out)
+		timer := time.NewTimer(maxWait)
+		timer.Stop()
+		defer timer.Stop()
+		batch := make([]T, 0, size)
+		flush := func() bool {
+			timer.Stop()
+			ok := send(ctx, out, batch)
+			batch = make([]T, 0, size)
+			return ok
+		}
+		for {
+			select {
+			case msg, ok := <-in:
+				if !ok {
+					if len(batch) > 0 {
+						flush()
+					}
+					return
+				}
+				if batch = append(batch, msg); len(batch) == 1 {
+					timer.Reset(maxWait)
+				}
+				if len(batch) == size && !flush() {
+					return
+				}
+			case <-timer.C:
+				if len(batch) > 0 && !flush() {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+func send[T any](ctx context.Context, ch chan<- T, msg T) bool {
+	select {
+	case ch <- msg:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,126 @@
+package pubsub_test
+
+import (
+	"context"
+	"github.com/clambin/go-common/pubsub"
+	"iter"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMap_Filter(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 10 {
+			in <- i
+		}
+		close(in)
+	}()
+
+	even := pubsub.Filter(t.Context(), in, func(i int) bool { return i%2 == 0 })
+	out := pubsub.Map(t.Context(), even, strconv.Itoa)
+	if got, want := slices.Collect(chanSeq(out)), []string{"0", "2", "4", "6", "8"}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestDebounce(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 5 {
+			in <- i
+		}
+		time.Sleep(100 * time.Millisecond)
+		for i := 5; i < 10; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+
+	out := pubsub.Debounce(t.Context(), in, 50*time.Millisecond)
+	if got, want := slices.Collect(chanSeq(out)), []int{4, 9}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestThrottle(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 5 {
+			in <- i
+		}
+		time.Sleep(150 * time.Millisecond)
+		in <- 5
+		close(in)
+	}()
+
+	out := pubsub.Throttle(t.Context(), in, 50*time.Millisecond)
+	if got, want := slices.Collect(chanSeq(out)), []int{0, 4, 5}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestThrottle_NoDuration(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 3 {
+			in <- i
+		}
+		close(in)
+	}()
+
+	out := pubsub.Throttle(t.Context(), in, 0)
+	if got, want := slices.Collect(chanSeq(out)), []int{0, 1, 2}; !slices.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	in := make(chan int)
+	go func() {
+		for i := range 5 {
+			in <- i
+		}
+		time.Sleep(100 * time.Millisecond)
+		in <- 5
+		close(in)
+	}()
+
+	out := pubsub.Batch(t.Context(), in, 2, 50*time.Millisecond)
+	want := [][]int{{0, 1}, {2, 3}, {4}, {5}}
+	got := slices.Collect(chanSeq(out))
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestOperators_Cancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	in := make(chan int)
+	outputs := []<-chan int{
+		pubsub.Map(ctx, in, func(i int) int { return i }),
+		pubsub.Filter(ctx, in, func(int) bool { return true }),
+		pubsub.Debounce(ctx, in, time.Second),
+		pubsub.Throttle(ctx, in, time.Second),
+	}
+	batch := pubsub.Batch(ctx, in, 10, time.Second)
+	cancel()
+	for _, out := range outputs {
+		for range out {
+		}
+	}
+	for range batch {
+	}
+}
+
+func chanSeq[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for msg := range ch {
+			if !yield(msg) {
+				return
+			}
+		}
+	}
+}
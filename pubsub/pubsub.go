@@ -1,35 +1,284 @@
 package pubsub
 
-import "sync"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"log/slog"
+	"sync"
+	"time"
+)
 
+// ErrClosed is returned when subscribing to, or publishing on, a closed Publisher.
+var ErrClosed = errors.New("publisher closed")
+
+// Publisher distributes published messages to all its subscribers. The zero value is ready to use.
 type Publisher[T any] struct {
-	clients map[<-chan T]chan T
-	lock    sync.RWMutex
+	clients map[<-chan T]*subscriber[T]
+	// clientsLock is held, along with lock, when changing clients. Unsubscribe and Close use it to find the
+	// subscribers to stop while Publish holds the read lock: taking the read lock would queue behind any caller
+	// waiting for the write lock, which in turn waits for Publish.
+	clientsLock sync.Mutex
+	history     []T
+	historySize int
+	buffer      int
+	seq         uint64
+	historyLock sync.Mutex
+	publishLock sync.Mutex
+	metrics     recorder
+	log         *Log[T]
+	slow        slowSubscriberOptions[T]
+	lock        sync.RWMutex
+	closed      bool
+}
+
+// PublisherOptions contains configuration options for a Publisher.
+type PublisherOptions[T any] struct {
+	// History is the number of most recently published messages that are replayed to a new subscriber,
+	// before it receives any live messages. Set History to 1 to give new subscribers the latest value.
+	// If zero, no messages are replayed.
+	History int
+	// Buffer is the number of live messages a subscriber's channel can hold before Publish waits for the subscriber
+	// to receive a message. A buffer absorbs short bursts and makes a subscriber's backlog visible in
+	// SubscriberStats.Pending. A subscriber is only reported as slow once its buffer is full. If zero, channels
+	// are unbuffered, except for any replayed history.
+	Buffer int
+	// Metrics measures the Publisher's behaviour. If nil, no metrics are collected.
+	Metrics Metrics
+	// Log stores each published message before it is delivered. If nil, messages are not stored.
+	Log *Log[T]
+	// SlowSubscriberThreshold is how long Publish waits for a subscriber to receive a message, before reporting
+	// the subscriber as slow. If zero, slow subscribers are not detected.
+	SlowSubscriberThreshold time.Duration
+	// OnSlowSubscriber is called when a subscriber is slow. It is called from Publish and must not call
+	// any of the Publisher's methods.
+	OnSlowSubscriber func(SubscriberStats[T])
+	// EvictSlowSubscribers unsubscribes slow subscribers, closing their channel. The message is not delivered.
+	// If false, Publish keeps waiting for the slow subscriber to receive the message.
+	EvictSlowSubscribers bool
+	// Logger logs slow subscribers. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// NewPublisher returns a Publisher configured with the provided options.
+func NewPublisher[T any](options PublisherOptions[T]) *Publisher[T] {
+	return &Publisher[T]{
+		historySize: max(options.History, 0),
+		buffer:      max(options.Buffer, 0),
+		metrics:     recorder{metrics: options.Metrics},
+		log:         options.Log,
+		slow: slowSubscriberOptions[T]{
+			threshold: options.SlowSubscriberThreshold,
+			callback:  options.OnSlowSubscriber,
+			evict:     options.EvictSlowSubscribers,
+			logger:    options.Logger,
+		},
+	}
+}
+
+type subscriber[T any] struct {
+	ch     chan T
+	filter func(T) bool
+	done   chan struct{}
+	once   sync.Once
+	stats  deliveryStats
+}
+
+// stop signals any ongoing Publish calls to stop delivering messages to the subscriber.
+func (s *subscriber[T]) stop() {
+	s.once.Do(func() { close(s.done) })
+}
+
+// Subscribe subscribes to the Publisher and returns the channel on which it will receive published messages.
+// If the Publisher keeps a history, the channel is buffered and already contains the replayed messages.
+// The channel also holds up to PublisherOptions.Buffer live messages.
+func (p *Publisher[T]) Subscribe() (<-chan T, error) {
+	ch, _, err := p.subscribe(context.Background(), nil, 0)
+	return ch, err
+}
+
+// SubscribeFunc subscribes to the Publisher, but only receives messages for which filter returns true.
+// A nil filter receives all messages.
+//
+// Publish evaluates the filters of all subscribers before delivering the message, so a subscriber only
+// blocks Publish for messages it actually receives. Filters are called concurrently with other calls to Publish
+// and must therefore be safe for concurrent use.
+func (p *Publisher[T]) SubscribeFunc(filter func(T) bool) (<-chan T, error) {
+	ch, _, err := p.subscribe(context.Background(), filter, 0)
+	return ch, err
 }
 
-func (p *Publisher[T]) Subscribe() <-chan T {
+// SubscribeContext subscribes to the Publisher. When the context is cancelled, the subscription is removed
+// and the channel is closed.
+func (p *Publisher[T]) SubscribeContext(ctx context.Context) (<-chan T, error) {
+	ch, _, err := p.subscribe(ctx, nil, 0)
+	return ch, err
+}
+
+// All returns an iterator over the published messages. Each iteration subscribes to the Publisher when it starts,
+// and unsubscribes when the loop ends, or the context is cancelled. If the Publisher is closed, the iteration ends.
+func (p *Publisher[T]) All(ctx context.Context) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		ch, err := p.SubscribeContext(ctx)
+		if err != nil {
+			return
+		}
+		defer p.Unsubscribe(ch)
+		for msg := range ch {
+			if !yield(msg) {
+				return
+			}
+		}
+	}
+}
+
+// subscribe adds a subscriber and replays all messages in the history with a sequence number of at least from.
+// It returns the sequence number of the first message the subscriber will receive. Without a filter, each next
+// message on the channel has the next sequence number.
+func (p *Publisher[T]) subscribe(ctx context.Context, filter func(T) bool, from uint64) (<-chan T, uint64, error) {
 	p.lock.Lock()
 	defer p.lock.Unlock()
+	if p.closed {
+		return nil, 0, ErrClosed
+	}
+	// the history holds the messages with sequence numbers p.seq-len(p.history)+1 up to p.seq
+	first := p.seq - uint64(len(p.history)) + 1
+	replay := p.history
+	if from > first {
+		replay = replay[min(from-first, uint64(len(replay))):]
+		first = min(from, p.seq+1)
+	}
+	s := &subscriber[T]{ch: make(chan T, len(replay)+p.buffer), filter: filter, done: make(chan struct{})}
+	// holding the write lock guarantees no messages are published until the history has been replayed
+	for _, msg := range replay {
+		if filter == nil || filter(msg) {
+			s.ch <- msg
+		}
+	}
+	p.clientsLock.Lock()
 	if p.clients == nil {
-		p.clients = make(map[<-chan T]chan T)
+		p.clients = make(map[<-chan T]*subscriber[T])
 	}
-	ch := make(chan T)
-	p.clients[ch] = ch
-	return ch
+	p.clients[s.ch] = s
+	p.clientsLock.Unlock()
+	p.metrics.subscribed(1)
+	if ctx.Done() != nil {
+		go func() {
+			select {
+			case <-ctx.Done():
+				p.Unsubscribe(s.ch)
+			case <-s.done:
+			}
+		}()
+	}
+	return s.ch, first, nil
 }
 
+// Unsubscribe removes the subscription and closes its channel. Any message currently being delivered to
+// the subscriber is dropped, so Unsubscribe does not need the channel to be drained.
 func (p *Publisher[T]) Unsubscribe(ch <-chan T) {
+	p.clientsLock.Lock()
+	s, ok := p.clients[ch]
+	p.clientsLock.Unlock()
+	if !ok {
+		return
+	}
+	s.stop()
+
 	p.lock.Lock()
 	defer p.lock.Unlock()
-	delete(p.clients, ch)
+	if p.clients[ch] == s {
+		p.clientsLock.Lock()
+		delete(p.clients, ch)
+		p.clientsLock.Unlock()
+		close(s.ch)
+		p.metrics.subscribed(-1)
+	}
+}
+
+// Close closes the channels of all subscribers. Once closed, Subscribe and Publish return ErrClosed.
+func (p *Publisher[T]) Close() {
+	p.clientsLock.Lock()
+	for _, s := range p.clients {
+		s.stop()
+	}
+	p.clientsLock.Unlock()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.clientsLock.Lock()
+	defer p.clientsLock.Unlock()
+	for ch, s := range p.clients {
+		s.stop()
+		delete(p.clients, ch)
+		close(s.ch)
+		p.metrics.subscribed(-1)
+	}
+	p.closed = true
 }
 
-func (p *Publisher[T]) Publish(data T) {
+// Publish delivers the message to all subscribers. If the Publisher keeps a history, calls to Publish are serialized,
+// so all subscribers receive messages in the order of their sequence number. Otherwise, concurrent calls to Publish
+// deliver their messages concurrently. If the Publisher has a Log, the message is only delivered once it is written
+// to the Log.
+func (p *Publisher[T]) Publish(data T) error {
+	_, err := p.publish(data)
+	return err
+}
+
+// publish implements Publish and returns the number of subscribers that received the message.
+func (p *Publisher[T]) publish(data T) (int, error) {
+	start := time.Now()
+	if p.historySize > 0 {
+		// a subscriber resuming from a sequence number (e.g. the SSE handler) relies on each subscriber
+		// receiving the messages in order, directly after the replayed history.
+		p.publishLock.Lock()
+		defer p.publishLock.Unlock()
+	}
 	p.lock.RLock()
 	defer p.lock.RUnlock()
-	for _, ch := range p.clients {
-		ch <- data
+	if p.closed {
+		return 0, ErrClosed
+	}
+	if p.log != nil {
+		if _, err := p.log.Append(data); err != nil {
+			return 0, fmt.Errorf("log: %w", err)
+		}
+	}
+	p.record(data)
+	recipients := make([]*subscriber[T], 0, len(p.clients))
+	for _, s := range p.clients {
+		if s.filter == nil || s.filter(data) {
+			recipients = append(recipients, s)
+		} else {
+			p.metrics.drop(DropFiltered)
+		}
+	}
+	var delivered int
+	for _, s := range recipients {
+		if p.deliver(s, data) {
+			delivered++
+		}
+	}
+	p.metrics.publish(start)
+	return delivered, nil
+}
+
+// record assigns the next sequence number to the message and adds it to the history. Publish calls record while
+// holding the read lock, so concurrent calls to Publish require a separate lock.
+func (p *Publisher[T]) record(data T) {
+	p.historyLock.Lock()
+	defer p.historyLock.Unlock()
+	p.seq++
+	if p.historySize == 0 {
+		return
+	}
+	if len(p.history) == p.historySize {
+		copy(p.history, p.history[1:])
+		p.history = p.history[:len(p.history)-1]
 	}
+	p.history = append(p.history, data)
 }
 
 func (p *Publisher[T]) Subscribers() int {
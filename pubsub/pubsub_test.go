@@ -1,14 +1,21 @@
 package pubsub_test
 
 import (
+	"context"
+	"errors"
 	"github.com/clambin/go-common/pubsub"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestPublisher(t *testing.T) {
 	var p pubsub.Publisher[int]
 
-	ch := p.Subscribe()
+	ch, err := p.Subscribe()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	defer p.Unsubscribe(ch)
 
 	if got := p.Subscribers(); got != 1 {
@@ -18,7 +25,9 @@ func TestPublisher(t *testing.T) {
 	const count = 10000
 	go func(n int) {
 		for i := range n {
-			p.Publish(i)
+			if err := p.Publish(i); err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
 		}
 	}(count)
 
@@ -28,3 +37,243 @@ func TestPublisher(t *testing.T) {
 		}
 	}
 }
+
+func TestPublisher_SubscribeFunc(t *testing.T) {
+	var p pubsub.Publisher[int]
+
+	even, _ := p.SubscribeFunc(func(i int) bool { return i%2 == 0 })
+	all, _ := p.Subscribe()
+
+	const count = 100
+	go func(n int) {
+		for i := range n {
+			_ = p.Publish(i)
+		}
+		p.Unsubscribe(even)
+		p.Unsubscribe(all)
+	}(count)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < count; i += 2 {
+			if val := <-even; val != i {
+				t.Errorf("even: got %d, want %d", val, i)
+			}
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range count {
+			if val := <-all; val != i {
+				t.Errorf("all: got %d, want %d", val, i)
+			}
+		}
+	}()
+	wg.Wait()
+}
+
+func TestPublisher_Unsubscribe(t *testing.T) {
+	var p pubsub.Publisher[int]
+	ch, _ := p.Subscribe()
+
+	// Unsubscribe must not wait for a pending message to be read
+	go func() { _ = p.Publish(1) }()
+	p.Unsubscribe(ch)
+	p.Unsubscribe(ch)
+
+	for range ch {
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+}
+
+func TestPublisher_SubscribeContext(t *testing.T) {
+	var p pubsub.Publisher[int]
+	ctx, cancel := context.WithCancel(t.Context())
+	ch, _ := p.SubscribeContext(ctx)
+
+	go func() {
+		for i := 0; ; i++ {
+			if p.Publish(i) != nil {
+				return
+			}
+		}
+	}()
+
+	<-ch
+	cancel()
+	for range ch {
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	p.Close()
+}
+
+func TestPublisher_Close(t *testing.T) {
+	var p pubsub.Publisher[int]
+	ch1, _ := p.Subscribe()
+	ch2, _ := p.Subscribe()
+
+	go func() { _ = p.Publish(1) }()
+	select {
+	case <-ch1:
+	case <-ch2:
+	}
+	p.Close()
+	p.Close()
+
+	for _, ch := range []<-chan int{ch1, ch2} {
+		for range ch {
+		}
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d, want 0", got)
+	}
+	if _, err := p.Subscribe(); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("Subscribe: got %v, want %v", err, pubsub.ErrClosed)
+	}
+	if err := p.Publish(1); !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("Publish: got %v, want %v", err, pubsub.ErrClosed)
+	}
+}
+
+func TestPublisher_History(t *testing.T) {
+	tests := []struct {
+		name    string
+		history int
+		want    []int
+	}{
+		{name: "none", history: 0, want: []int{10}},
+		{name: "latest", history: 1, want: []int{4, 10}},
+		{name: "last 3", history: 3, want: []int{2, 3, 4, 10}},
+		{name: "more than published", history: 10, want: []int{0, 1, 2, 3, 4, 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{History: tt.history})
+			for i := range 5 {
+				_ = p.Publish(i)
+			}
+
+			ch, _ := p.Subscribe()
+			go func() { _ = p.Publish(10) }()
+
+			for _, want := range tt.want {
+				if got := <-ch; got != want {
+					t.Errorf("got %d, want %d", got, want)
+				}
+			}
+			p.Close()
+		})
+	}
+}
+
+func TestPublisher_History_Concurrent(t *testing.T) {
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{History: 10})
+	const count = 1000
+	go func() {
+		for i := range count {
+			_ = p.Publish(i)
+		}
+	}()
+
+	// a subscriber joining at any time must receive an uninterrupted sequence, ending with the last published message
+	for range 10 {
+		ch, _ := p.Subscribe()
+		last := -1
+		for val := range ch {
+			if last != -1 && val != last+1 {
+				t.Fatalf("got %d, want %d", val, last+1)
+			}
+			if last = val; last == count-1 {
+				break
+			}
+		}
+		p.Unsubscribe(ch)
+	}
+}
+
+func TestPublisher_Publish_Concurrent(t *testing.T) {
+	tests := []struct {
+		name       string
+		history    int
+		concurrent bool
+	}{
+		{name: "without history", history: 0, concurrent: true},
+		{name: "with history", history: 1, concurrent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{History: tt.history})
+			defer p.Close()
+			// this subscriber never reads, so publishing 1 blocks until the Publisher is closed
+			_, _ = p.SubscribeFunc(func(i int) bool { return i == 1 })
+			ch, _ := p.SubscribeFunc(func(i int) bool { return i == 2 })
+
+			go func() { _ = p.Publish(1) }()
+			time.Sleep(100 * time.Millisecond)
+			go func() { _ = p.Publish(2) }()
+
+			select {
+			case <-ch:
+				if !tt.concurrent {
+					t.Error("Publish should wait for the previous call to complete")
+				}
+			case <-time.After(100 * time.Millisecond):
+				if tt.concurrent {
+					t.Error("Publish should not wait for the previous call to complete")
+				}
+			}
+		})
+	}
+}
+
+func TestPublisher_All(t *testing.T) {
+	var p pubsub.Publisher[int]
+	go func() {
+		for i := 0; ; i++ {
+			if p.Publish(i) != nil {
+				return
+			}
+		}
+	}()
+
+	// break ends the subscription
+	var want int
+	for val := range p.All(t.Context()) {
+		if val < want {
+			t.Fatalf("got %d, want at least %d", val, want)
+		}
+		if want = val + 1; want >= 10 {
+			break
+		}
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d subscribers, want 0", got)
+	}
+
+	// cancelling the context ends the subscription
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	var count int
+	for range p.All(ctx) {
+		if count++; count == 10 {
+			cancel()
+		}
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d subscribers, want 0", got)
+	}
+
+	// a closed publisher yields no messages
+	p.Close()
+	for range p.All(t.Context()) {
+		t.Error("unexpected message")
+	}
+}
@@ -0,0 +1,155 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNoQuorum is returned when a request did not receive the required number of replies.
+var ErrNoQuorum = errors.New("no quorum")
+
+// errBusy is the reply of a responder whose queue is full.
+var errBusy = errors.New("responder busy")
+
+// Requester implements request/reply messaging: a request is sent to all responders and Request collects their replies.
+type Requester[Req, Resp any] struct {
+	publisher Publisher[request[Req, Resp]]
+	quorum    int
+	queueSize int
+}
+
+// RequesterOptions contains configuration options for a Requester.
+type RequesterOptions struct {
+	// Quorum is the number of replies after which Request returns. If zero, Request waits for all responders.
+	Quorum int
+	// QueueSize is the number of requests a responder queues while it is handling a request. If the queue is full,
+	// the responder replies that it is busy, rather than holding up the request. Defaults to 16.
+	QueueSize int
+}
+
+type request[Req, Resp any] struct {
+	ctx     context.Context
+	msg     Req
+	replies chan<- reply[Resp]
+}
+
+type reply[Resp any] struct {
+	msg Resp
+	err error
+}
+
+// NewRequester returns a Requester configured with the provided options.
+func NewRequester[Req, Resp any](options RequesterOptions) *Requester[Req, Resp] {
+	return &Requester[Req, Resp]{quorum: max(options.Quorum, 0), queueSize: max(options.QueueSize, 0)}
+}
+
+// Respond registers a responder. The handler is called for each request. If the handler returns an error,
+// the responder does not reply to that request. Use the returned Subscription to remove the responder.
+//
+// Requests are queued for the handler, so a slow handler doesn't delay requests for other responders
+// (see RequesterOptions.QueueSize). Requests that expire while queued are not passed to the handler.
+func (r *Requester[Req, Resp]) Respond(handler func(context.Context, Req) (Resp, error)) (*Subscription, error) {
+	queueSize := r.queueSize
+	if queueSize == 0 {
+		queueSize = 16
+	}
+	queue := make(chan request[Req, Resp], queueSize)
+	// Publish waits for each responder to receive the request, so queue the request without blocking
+	subscription, err := r.publisher.Handle(func(_ context.Context, req request[Req, Resp]) error {
+		select {
+		case queue <- req:
+		default:
+			// replies only has room for the responders at the start of the request. Publish waits for this
+			// function to return, so reply from a separate goroutine in case replies is full.
+			go req.reply(reply[Resp]{err: errBusy})
+		}
+		return nil
+	}, HandleOptions[request[Req, Resp]]{})
+	if err != nil {
+		return nil, err
+	}
+
+	s := Subscription{
+		stop:   subscription.stop,
+		done:   make(chan struct{}),
+		cancel: subscription.cancel,
+	}
+	go func() {
+		<-subscription.done
+		close(queue)
+	}()
+	go func() {
+		defer close(s.done)
+		for req := range queue {
+			if req.ctx.Err() == nil {
+				var resp Resp
+				// like Handle, recover from a panic in the handler
+				err := handle(req.ctx, func(ctx context.Context, msg Req) (err error) {
+					resp, err = handler(ctx, msg)
+					return err
+				}, req.msg)
+				req.reply(reply[Resp]{msg: resp, err: err})
+			}
+		}
+	}()
+	return &s, nil
+}
+
+// reply sends the reply, unless Request has already returned.
+func (req request[Req, Resp]) reply(resp reply[Resp]) {
+	select {
+	case req.replies <- resp:
+	case <-req.ctx.Done():
+	}
+}
+
+// Responders returns the number of registered responders.
+func (r *Requester[Req, Resp]) Responders() int {
+	return r.publisher.Subscribers()
+}
+
+// Request sends the request to all responders and returns their replies. It returns once the quorum has been reached,
+// all responders have answered, or the context expires, whichever comes first. If fewer replies than the quorum
+// were received (or, without a quorum, not all responders replied), Request returns the received replies,
+// along with ErrNoQuorum.
+//
+// The context passed to the responders is cancelled when Request returns.
+func (r *Requester[Req, Resp]) Request(ctx context.Context, msg Req) ([]Resp, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	replies := make(chan reply[Resp], r.publisher.Subscribers())
+	// only wait for the responders that received the request: a responder may have stopped in the meantime
+	responders, err := r.publisher.publish(request[Req, Resp]{ctx: ctx, msg: msg, replies: replies})
+	if err != nil {
+		return nil, err
+	}
+	quorum := r.quorum
+	if quorum == 0 {
+		quorum = responders
+	}
+	if quorum == 0 {
+		return nil, fmt.Errorf("%w: no responders", ErrNoQuorum)
+	}
+
+	responses := make([]Resp, 0, quorum)
+	for answered := 0; answered < responders && len(responses) < quorum; answered++ {
+		select {
+		case resp := <-replies:
+			if resp.err == nil {
+				responses = append(responses, resp.msg)
+			}
+		case <-ctx.Done():
+			return responses, fmt.Errorf("%w: %d/%d replies: %w", ErrNoQuorum, len(responses), quorum, ctx.Err())
+		}
+	}
+	if len(responses) < quorum {
+		return responses, fmt.Errorf("%w: %d/%d replies", ErrNoQuorum, len(responses), quorum)
+	}
+	return responses, nil
+}
+
+// Close removes all responders. Once closed, Request and Respond return ErrClosed.
+func (r *Requester[Req, Resp]) Close() {
+	r.publisher.Close()
+}
@@ -0,0 +1,161 @@
+package pubsub_test
+
+import (
+	"context"
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRequester_Request(t *testing.T) {
+	tests := []struct {
+		name    string
+		quorum  int
+		timeout time.Duration
+		want    []string
+		wantErr bool
+	}{
+		{name: "all responders", want: []string{"a", "b"}, timeout: 100 * time.Millisecond, wantErr: true},
+		{name: "quorum", quorum: 2, timeout: time.Second, want: []string{"a", "b"}},
+		{name: "quorum not reached", quorum: 4, timeout: 100 * time.Millisecond, want: []string{"a", "b"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := pubsub.NewRequester[string, string](pubsub.RequesterOptions{Quorum: tt.quorum})
+			defer r.Close()
+			for _, name := range []string{"a", "b"} {
+				_, _ = r.Respond(func(_ context.Context, req string) (string, error) {
+					return name, nil
+				})
+			}
+			_, _ = r.Respond(func(context.Context, string) (string, error) {
+				return "", errors.New("failed")
+			})
+			// a responder that doesn't reply in time
+			_, _ = r.Respond(func(ctx context.Context, _ string) (string, error) {
+				<-ctx.Done()
+				return "late", nil
+			})
+			if got := r.Responders(); got != 4 {
+				t.Fatalf("got %d responders, want 4", got)
+			}
+
+			ctx, cancel := context.WithTimeout(t.Context(), tt.timeout)
+			defer cancel()
+			got, err := r.Request(ctx, "status")
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, pubsub.ErrNoQuorum) {
+				t.Errorf("got %v, want %v", err, pubsub.ErrNoQuorum)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequester_Request_AllResponded(t *testing.T) {
+	var r pubsub.Requester[int, int]
+	defer r.Close()
+
+	if _, err := r.Request(t.Context(), 1); !errors.Is(err, pubsub.ErrNoQuorum) {
+		t.Errorf("got %v, want %v", err, pubsub.ErrNoQuorum)
+	}
+
+	s, _ := r.Respond(func(_ context.Context, req int) (int, error) { return 2 * req, nil })
+	got, err := r.Request(t.Context(), 21)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !slices.Equal(got, []int{42}) {
+		t.Errorf("got %v, want [42]", got)
+	}
+	if err = s.Stop(t.Context()); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if got := r.Responders(); got != 0 {
+		t.Errorf("got %d responders, want 0", got)
+	}
+}
+
+func TestRequester_Request_BusyResponder(t *testing.T) {
+	r := pubsub.NewRequester[string, string](pubsub.RequesterOptions{Quorum: 1, QueueSize: 1})
+	defer r.Close()
+	_, _ = r.Respond(func(context.Context, string) (string, error) { return "fast", nil })
+	// a responder that ignores the context and takes longer than the requests
+	_, _ = r.Respond(func(context.Context, string) (string, error) {
+		time.Sleep(time.Second)
+		return "slow", nil
+	})
+
+	// the slow responder's handler and queue are busy, but requests still reach quorum through the fast responder
+	for range 4 {
+		ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+		start := time.Now()
+		got, err := r.Request(ctx, "status")
+		cancel()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Equal(got, []string{"fast"}) {
+			t.Errorf("got %v, want [fast]", got)
+		}
+		if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+			t.Errorf("request took %v", elapsed)
+		}
+	}
+}
+
+func TestRequester_Request_Panic(t *testing.T) {
+	var r pubsub.Requester[int, int]
+	defer r.Close()
+	_, _ = r.Respond(func(context.Context, int) (int, error) { panic("failed") })
+	_, _ = r.Respond(func(_ context.Context, req int) (int, error) { return 2 * req, nil })
+
+	got, err := r.Request(t.Context(), 21)
+	if !errors.Is(err, pubsub.ErrNoQuorum) {
+		t.Errorf("got %v, want %v", err, pubsub.ErrNoQuorum)
+	}
+	if !slices.Equal(got, []int{42}) {
+		t.Errorf("got %v, want [42]", got)
+	}
+}
+
+func TestRequester_Request_StoppedResponder(t *testing.T) {
+	var r pubsub.Requester[int, int]
+	defer r.Close()
+	_, _ = r.Respond(func(_ context.Context, req int) (int, error) { return req, nil })
+
+	// responders that stop while requests are being sent
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() {
+		for ctx.Err() == nil {
+			s, _ := r.Respond(func(_ context.Context, req int) (int, error) { return req, nil })
+			_ = s.Stop(ctx)
+		}
+	}()
+
+	// without a quorum or a deadline, Request only waits for the responders that received the request
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := range 1000 {
+			if _, err := r.Request(t.Context(), i); err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Request blocked")
+	}
+}
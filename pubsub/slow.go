@@ -0,0 +1,125 @@
+package pubsub
+
+import (
+	"log/slog"
+	"sync/atomic"
+	"time"
+)
+
+// SubscriberStats contains the delivery statistics of a subscriber.
+type SubscriberStats[T any] struct {
+	// Subscription is the subscriber's channel, as returned by Subscribe.
+	Subscription <-chan T
+	// Delivered is the number of messages delivered to the subscriber.
+	Delivered uint64
+	// AverageLatency is the average time Publish waited for the subscriber to receive a message.
+	AverageLatency time.Duration
+	// MaxLatency is the longest time Publish waited for the subscriber to receive a message.
+	MaxLatency time.Duration
+	// Pending is the number of messages in the subscriber's channel that haven't been received yet, i.e. its backlog.
+	// Pending is always zero for an unbuffered channel: see PublisherOptions.Buffer.
+	Pending int
+	// Blocked is how long the current message has been waiting to be received. Only set when reporting a slow subscriber.
+	Blocked time.Duration
+}
+
+type slowSubscriberOptions[T any] struct {
+	threshold time.Duration
+	callback  func(SubscriberStats[T])
+	evict     bool
+	logger    *slog.Logger
+}
+
+type deliveryStats struct {
+	delivered  atomic.Uint64
+	latency    atomic.Int64
+	maxLatency atomic.Int64
+}
+
+func (d *deliveryStats) measure(latency time.Duration) {
+	d.delivered.Add(1)
+	d.latency.Add(int64(latency))
+	for {
+		current := d.maxLatency.Load()
+		if int64(latency) <= current || d.maxLatency.CompareAndSwap(current, int64(latency)) {
+			return
+		}
+	}
+}
+
+func (s *subscriber[T]) statistics() SubscriberStats[T] {
+	stats := SubscriberStats[T]{
+		Subscription: s.ch,
+		Delivered:    s.stats.delivered.Load(),
+		MaxLatency:   time.Duration(s.stats.maxLatency.Load()),
+		Pending:      len(s.ch),
+	}
+	if stats.Delivered > 0 {
+		stats.AverageLatency = time.Duration(s.stats.latency.Load() / int64(stats.Delivered))
+	}
+	return stats
+}
+
+// SubscriberStats returns the delivery statistics of all subscribers.
+func (p *Publisher[T]) SubscriberStats() []SubscriberStats[T] {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	stats := make([]SubscriberStats[T], 0, len(p.clients))
+	for _, s := range p.clients {
+		stats = append(stats, s.statistics())
+	}
+	return stats
+}
+
+// deliver sends the message to the subscriber and returns whether the subscriber received it. If the subscriber
+// doesn't receive the message within the threshold, it is reported as slow and, if configured, evicted.
+func (p *Publisher[T]) deliver(s *subscriber[T], data T) bool {
+	start := time.Now()
+	var slow <-chan time.Time
+	if p.slow.threshold > 0 {
+		timer := time.NewTimer(p.slow.threshold)
+		defer timer.Stop()
+		slow = timer.C
+	}
+	for {
+		select {
+		case s.ch <- data:
+			s.stats.measure(time.Since(start))
+			p.metrics.deliver()
+			return true
+		case <-s.done:
+			p.metrics.drop(DropUnsubscribed)
+			return false
+		case <-slow:
+			p.reportSlow(s, time.Since(start))
+			if p.slow.evict {
+				s.stop()
+				// we hold the read lock, so Unsubscribe can only complete once Publish returns
+				go p.Unsubscribe(s.ch)
+				p.metrics.drop(DropEvicted)
+				return false
+			}
+			// report a slow subscriber only once per message
+			slow = nil
+		}
+	}
+}
+
+func (p *Publisher[T]) reportSlow(s *subscriber[T], blocked time.Duration) {
+	stats := s.statistics()
+	stats.Blocked = blocked
+	logger := p.slow.logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Warn("slow subscriber detected",
+		"blocked", blocked,
+		"pending", stats.Pending,
+		"delivered", stats.Delivered,
+		"averageLatency", stats.AverageLatency,
+		"evict", p.slow.evict,
+	)
+	if p.slow.callback != nil {
+		p.slow.callback(stats)
+	}
+}
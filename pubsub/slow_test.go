@@ -0,0 +1,85 @@
+package pubsub_test
+
+import (
+	"bytes"
+	"github.com/clambin/go-common/pubsub"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPublisher_EvictSlowSubscribers(t *testing.T) {
+	var slow atomic.Int32
+	var buf bytes.Buffer
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{
+		SlowSubscriberThreshold: 50 * time.Millisecond,
+		OnSlowSubscriber:        func(pubsub.SubscriberStats[int]) { slow.Add(1) },
+		EvictSlowSubscribers:    true,
+		Logger:                  slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+	defer p.Close()
+
+	fast, _ := p.Subscribe()
+	go func() {
+		for range fast {
+		}
+	}()
+	stuck, _ := p.Subscribe()
+
+	// the stuck subscriber doesn't block delivery to the fast subscriber beyond the threshold
+	for i := range 3 {
+		if err := p.Publish(i); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := slow.Load(); got != 1 {
+		t.Errorf("got %d slow subscriber reports, want 1", got)
+	}
+	if _, ok := <-stuck; ok {
+		t.Error("stuck subscriber should have been evicted")
+	}
+	if got := p.Subscribers(); got != 1 {
+		t.Errorf("got %d subscribers, want 1", got)
+	}
+	stats := p.SubscriberStats()
+	if len(stats) != 1 || stats[0].Subscription != fast || stats[0].Delivered != 3 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if !strings.Contains(buf.String(), "slow subscriber detected") {
+		t.Errorf("slow subscriber not logged: %s", buf.String())
+	}
+}
+
+func TestPublisher_SlowSubscriber(t *testing.T) {
+	reports := make(chan pubsub.SubscriberStats[int], 1)
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[int]{
+		Buffer:                  2,
+		SlowSubscriberThreshold: 10 * time.Millisecond,
+		OnSlowSubscriber:        func(stats pubsub.SubscriberStats[int]) { reports <- stats },
+		Logger:                  slog.New(slog.DiscardHandler),
+	})
+	defer p.Close()
+
+	// the buffer holds the first messages, without blocking Publish
+	ch, _ := p.Subscribe()
+	for i := range 2 {
+		_ = p.Publish(i)
+	}
+	if stats := p.SubscriberStats(); len(stats) != 1 || stats[0].Pending != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// once the buffer is full, the subscriber is slow, but isn't evicted: the message is delivered once it is received
+	go func() { _ = p.Publish(2) }()
+	stats := <-reports
+	if stats.Subscription != ch || stats.Pending != 2 || stats.Blocked < 10*time.Millisecond {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	for want := range 3 {
+		if got := <-ch; got != want {
+			t.Errorf("got %d, want %d", got, want)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package pubsub
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// SSEOptions contains configuration options for an SSE handler.
+type SSEOptions[T any] struct {
+	// Encode encodes a message as the event's data. If nil, messages are encoded as JSON.
+	Encode func(T) ([]byte, error)
+	// Event sets the event type of each event. If empty, no event type is sent (i.e. the "message" type is used).
+	Event string
+	// Retry tells the client how long to wait before reconnecting. If zero, the client's default is used.
+	Retry time.Duration
+	// KeepAlive determines how often a comment is sent to keep an idle connection alive. Defaults to 15 seconds.
+	KeepAlive time.Duration
+	// Logger logs messages that could not be encoded. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// NewSSEHandler returns an http.Handler that streams the messages of a Publisher to HTTP clients as Server-Sent Events.
+//
+// Each connection subscribes to the Publisher and unsubscribes when the client disconnects. Each event's id is the
+// message's sequence number in the Publisher. If the Publisher keeps a history, a reconnecting client that sends
+// a Last-Event-ID header first receives the messages it missed, as far as they are still in the history.
+// Without a history, concurrent calls to Publish may deliver messages out of order, so ids are only reliable
+// if the Publisher keeps a history.
+func NewSSEHandler[T any](p *Publisher[T], options SSEOptions[T]) http.Handler {
+	if options.Encode == nil {
+		options.Encode = func(msg T) ([]byte, error) { return json.Marshal(msg) }
+	}
+	if options.KeepAlive <= 0 {
+		options.KeepAlive = 15 * time.Second
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	return &sseHandler[T]{publisher: p, options: options}
+}
+
+type sseHandler[T any] struct {
+	publisher *Publisher[T]
+	options   SSEOptions[T]
+}
+
+func (h *sseHandler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var from uint64
+	if lastID, err := strconv.ParseUint(r.Header.Get("Last-Event-ID"), 10, 64); err == nil {
+		from = lastID + 1
+	}
+	ch, id, err := h.publisher.subscribe(r.Context(), nil, from)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.publisher.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	if err = rc.Flush(); err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(h.options.KeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case msg, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err = h.writeEvent(w, id, msg); err == nil {
+				err = rc.Flush()
+			}
+			id++
+		case <-keepAlive.C:
+			if _, err = w.Write([]byte(": keep-alive\n\n")); err == nil {
+				err = rc.Flush()
+			}
+		case <-r.Context().Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (h *sseHandler[T]) writeEvent(w http.ResponseWriter, id uint64, msg T) error {
+	data, err := h.options.Encode(msg)
+	if err != nil {
+		h.options.Logger.Warn("failed to encode message", "id", id, "err", err)
+		return nil
+	}
+	var buf bytes.Buffer
+	_, _ = fmt.Fprintf(&buf, "id: %d\n", id)
+	if h.options.Event != "" {
+		_, _ = fmt.Fprintf(&buf, "event: %s\n", h.options.Event)
+	}
+	if h.options.Retry > 0 {
+		_, _ = fmt.Fprintf(&buf, "retry: %d\n", h.options.Retry.Milliseconds())
+	}
+	if len(data) == 0 {
+		buf.WriteString("data: \n")
+	}
+	for line := range bytes.Lines(data) {
+		_, _ = fmt.Fprintf(&buf, "data: %s\n", bytes.TrimRight(line, "\r\n"))
+	}
+	buf.WriteString("\n")
+	_, err = w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,100 @@
+package pubsub_test
+
+import (
+	"bufio"
+	"github.com/clambin/go-common/pubsub"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEHandler(t *testing.T) {
+	type message struct {
+		Value int `json:"value"`
+	}
+	p := pubsub.NewPublisher(pubsub.PublisherOptions[message]{History: 5})
+	for i := range 3 {
+		_ = p.Publish(message{Value: i})
+	}
+
+	s := httptest.NewServer(pubsub.NewSSEHandler(p, pubsub.SSEOptions[message]{
+		Event:     "update",
+		Retry:     time.Second,
+		KeepAlive: 10 * time.Millisecond,
+	}))
+	defer s.Close()
+
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, s.URL, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("got content type %q", got)
+	}
+
+	go func() {
+		for i := 3; ; i++ {
+			if p.Publish(message{Value: i}) != nil {
+				return
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}()
+
+	// skip the messages before Last-Event-ID; receive the rest from history, followed by live messages
+	want := []string{
+		"id: 2", "event: update", "retry: 1000", `data: {"value":1}`, "",
+		"id: 3", "event: update", "retry: 1000", `data: {"value":2}`, "",
+		"id: 4", "event: update", "retry: 1000", `data: {"value":3}`, "",
+	}
+	var sawKeepAlive bool
+	r := bufio.NewReader(resp.Body)
+	for len(want) > 0 {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if strings.HasPrefix(line, ":") {
+			sawKeepAlive = true
+			_, _ = r.ReadString('\n')
+			continue
+		}
+		if line != want[0] {
+			t.Fatalf("got %q, want %q", line, want[0])
+		}
+		want = want[1:]
+	}
+	for !sawKeepAlive {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		sawKeepAlive = strings.HasPrefix(line, ": keep-alive")
+	}
+	_ = resp.Body.Close()
+
+	// client disconnected: the handler unsubscribes
+	deadline := time.Now().Add(time.Second)
+	for p.Subscribers() > 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := p.Subscribers(); got != 0 {
+		t.Errorf("got %d subscribers, want 0", got)
+	}
+	p.Close()
+}
+
+func TestSSEHandler_Closed(t *testing.T) {
+	var p pubsub.Publisher[int]
+	p.Close()
+	w := httptest.NewRecorder()
+	pubsub.NewSSEHandler(&p, pubsub.SSEOptions[int]{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,162 @@
+package pubsub
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// StreamOptions contains configuration options for NewStreamHandler.
+type StreamOptions[T any] struct {
+	// Codec encodes the messages. If nil, messages are encoded as JSON.
+	Codec Codec[T]
+	// Logger logs messages that could not be encoded. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// NewStreamHandler returns an http.Handler that streams the messages of a Publisher to the client,
+// using chunked transfer encoding. Use Relay to receive the stream in another process.
+//
+// Each connection subscribes to the Publisher and unsubscribes when the client disconnects.
+func NewStreamHandler[T any](p *Publisher[T], options StreamOptions[T]) http.Handler {
+	if options.Codec == nil {
+		options.Codec = JSONCodec[T]{}
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+	return &streamHandler[T]{publisher: p, options: options}
+}
+
+type streamHandler[T any] struct {
+	publisher *Publisher[T]
+	options   StreamOptions[T]
+}
+
+func (h *streamHandler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.publisher.SubscribeContext(r.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	defer h.publisher.Unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	rc := http.NewResponseController(w)
+	if err = rc.Flush(); err != nil {
+		return
+	}
+	for msg := range ch {
+		data, err := h.options.Codec.Encode(msg)
+		if err != nil {
+			h.options.Logger.Warn("failed to encode message", "err", err)
+			continue
+		}
+		if _, err = w.Write(makeRecord(data)); err == nil {
+			err = rc.Flush()
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// RelayOptions contains configuration options for Relay.
+type RelayOptions[T any] struct {
+	// Codec decodes the messages. If nil, messages are decoded as JSON.
+	Codec Codec[T]
+	// HTTPClient performs the HTTP request. Defaults to http.DefaultClient. The client should not have a timeout,
+	// as this would end the stream.
+	HTTPClient *http.Client
+	// MinBackoff is the time to wait before reconnecting after the connection fails. The wait time doubles on each
+	// consecutive failure, up to MaxBackoff. Defaults to 100 ms and 30 s respectively.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// MaxRecordSize is the maximum size (in bytes) of an encoded message. A larger message means the URL doesn't
+	// serve a stream (or the stream is corrupt), so Relay drops the connection. Defaults to 16 MiB.
+	MaxRecordSize int64
+	// Logger logs connection failures and messages that could not be decoded. Defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Relay connects to a stream served by NewStreamHandler and publishes the received messages to the Publisher.
+// When the connection fails, Relay reconnects with exponential backoff. Relay returns when the context is cancelled,
+// or with ErrClosed when the Publisher is closed.
+func Relay[T any](ctx context.Context, url string, p *Publisher[T], options RelayOptions[T]) error {
+	if options.Codec == nil {
+		options.Codec = JSONCodec[T]{}
+	}
+	if options.HTTPClient == nil {
+		options.HTTPClient = http.DefaultClient
+	}
+	if options.MinBackoff <= 0 {
+		options.MinBackoff = 100 * time.Millisecond
+	}
+	if options.MaxBackoff < options.MinBackoff {
+		options.MaxBackoff = max(30*time.Second, options.MinBackoff)
+	}
+	if options.MaxRecordSize <= 0 {
+		options.MaxRecordSize = 16 << 20
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+
+	backoff := options.MinBackoff
+	for {
+		connected, err := relay(ctx, url, p, options)
+		if errors.Is(err, ErrClosed) {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		if connected {
+			backoff = options.MinBackoff
+		}
+		options.Logger.Warn("stream disconnected", "url", url, "err", err, "backoff", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil
+		}
+		backoff = min(2*backoff, options.MaxBackoff)
+	}
+}
+
+// relay receives the messages of a single connection. It returns whether the connection was established.
+func relay[T any](ctx context.Context, url string, p *Publisher[T], options RelayOptions[T]) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := options.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	r := bufio.NewReader(resp.Body)
+	for {
+		data, err := readRecord(r, options.MaxRecordSize)
+		if err != nil {
+			return true, err
+		}
+		msg, err := options.Codec.Decode(data)
+		if err != nil {
+			options.Logger.Warn("failed to decode message", "url", url, "err", err)
+			continue
+		}
+		if err = p.Publish(msg); err != nil {
+			return true, err
+		}
+	}
+}
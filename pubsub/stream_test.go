@@ -0,0 +1,145 @@
+package pubsub_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/clambin/go-common/pubsub"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRelay(t *testing.T) {
+	var remote pubsub.Publisher[string]
+	s := httptest.NewServer(pubsub.NewStreamHandler(&remote, pubsub.StreamOptions[string]{}))
+	defer s.Close()
+
+	var local pubsub.Publisher[string]
+	ch, _ := local.Subscribe()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error)
+	go func() {
+		errCh <- pubsub.Relay(ctx, s.URL, &local, pubsub.RelayOptions[string]{
+			MinBackoff: 10 * time.Millisecond,
+			Logger:     slog.New(slog.DiscardHandler),
+		})
+	}()
+
+	for _, msg := range []string{"foo", "bar"} {
+		waitFor(t, func() bool { return remote.Subscribers() == 1 })
+		_ = remote.Publish(msg)
+		if got := <-ch; got != msg {
+			t.Errorf("got %q, want %q", got, msg)
+		}
+		// drop the connection: the relay reconnects
+		s.CloseClientConnections()
+		waitFor(t, func() bool { return remote.Subscribers() == 0 })
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRelay_EmptyMessage(t *testing.T) {
+	var remote pubsub.Publisher[string]
+	s := httptest.NewServer(pubsub.NewStreamHandler(&remote, pubsub.StreamOptions[string]{Codec: rawCodec{}}))
+	defer s.Close()
+
+	var local pubsub.Publisher[string]
+	ch, _ := local.Subscribe()
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error)
+	go func() {
+		errCh <- pubsub.Relay(ctx, s.URL, &local, pubsub.RelayOptions[string]{
+			Codec:  rawCodec{},
+			Logger: slog.New(slog.DiscardHandler),
+		})
+	}()
+
+	// an empty message doesn't break the stream
+	waitFor(t, func() bool { return remote.Subscribers() == 1 })
+	go func() {
+		for _, msg := range []string{"", "a"} {
+			_ = remote.Publish(msg)
+		}
+	}()
+	for _, want := range []string{"", "a"} {
+		if got := <-ch; got != want {
+			t.Errorf("got %q, want %q", got, want)
+		}
+	}
+
+	cancel()
+	if err := <-errCh; err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRelay_Closed(t *testing.T) {
+	var remote pubsub.Publisher[string]
+	s := httptest.NewServer(pubsub.NewStreamHandler(&remote, pubsub.StreamOptions[string]{}))
+	defer s.Close()
+
+	var local pubsub.Publisher[string]
+	local.Close()
+	errCh := make(chan error)
+	go func() {
+		errCh <- pubsub.Relay(t.Context(), s.URL, &local, pubsub.RelayOptions[string]{Logger: slog.New(slog.DiscardHandler)})
+	}()
+	waitFor(t, func() bool { return remote.Subscribers() == 1 })
+	_ = remote.Publish("foo")
+	if err := <-errCh; !errors.Is(err, pubsub.ErrClosed) {
+		t.Errorf("got %v, want %v", err, pubsub.ErrClosed)
+	}
+}
+
+func TestRelay_NotAStream(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("<!DOCTYPE html><html></html>"))
+	}))
+	defer s.Close()
+
+	var local pubsub.Publisher[string]
+	var logs bytes.Buffer
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+	// the first bytes of the page are read as the size of a record, which exceeds MaxRecordSize
+	err := pubsub.Relay(ctx, s.URL, &local, pubsub.RelayOptions[string]{
+		MinBackoff: 10 * time.Millisecond,
+		Logger:     slog.New(slog.NewTextHandler(&logs, nil)),
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if !strings.Contains(logs.String(), pubsub.ErrCorrupt.Error()) {
+		t.Errorf("connection not dropped: %s", logs.String())
+	}
+}
+
+func TestStreamHandler_Closed(t *testing.T) {
+	var p pubsub.Publisher[int]
+	p.Close()
+	w := httptest.NewRecorder()
+	pubsub.NewStreamHandler(&p, pubsub.StreamOptions[int]{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("got %d, want %d", w.Code, http.StatusServiceUnavailable)
+	}
+}
+
+func waitFor(t *testing.T, f func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !f() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -0,0 +1,122 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+	"maps"
+	"slices"
+)
+
+// Bag holds a multiset: a set of values where each value can occur more than once.
+type Bag[T comparable] map[T]int
+
+// BagEntry is a value in a Bag and the number of times it occurs.
+type BagEntry[T comparable] struct {
+	Value T
+	Count int
+}
+
+// NewBag creates a new bag containing the optional values. A value that occurs more than once is counted accordingly.
+func NewBag[T comparable](values ...T) Bag[T] {
+	b := make(Bag[T], len(values))
+	for _, value := range values {
+		b[value]++
+	}
+	return b
+}
+
+// Add adds n occurrences of value to the bag. If n is not positive, the bag is not changed.
+func (b Bag[T]) Add(value T, n int) {
+	if n > 0 {
+		b[value] += n
+	}
+}
+
+// Remove removes n occurrences of value from the bag. If the bag contains n or fewer occurrences, the value is removed.
+func (b Bag[T]) Remove(value T, n int) {
+	if n <= 0 {
+		return
+	}
+	if b[value] > n {
+		b[value] -= n
+	} else {
+		delete(b, value)
+	}
+}
+
+// Count returns the number of occurrences of value in the bag
+func (b Bag[T]) Count(value T) int {
+	return b[value]
+}
+
+// Size returns the total number of occurrences of all values in the bag. Use len() for the number of distinct values.
+func (b Bag[T]) Size() int {
+	var size int
+	for _, count := range b {
+		size += count
+	}
+	return size
+}
+
+// Distinct returns the values in the bag as a Set
+func (b Bag[T]) Distinct() Set[T] {
+	return Collect(maps.Keys(b))
+}
+
+// All returns an iterator over all values in the bag and their count. Order is not guaranteed.
+func (b Bag[T]) All() iter.Seq2[T, int] {
+	return maps.All(b)
+}
+
+// MostCommon returns the k values that occur most often, from most to least common. Values with the same count
+// are returned in no particular order. If k is negative, or larger than the number of distinct values, all values are returned.
+func (b Bag[T]) MostCommon(k int) []BagEntry[T] {
+	entries := make([]BagEntry[T], 0, len(b))
+	for value, count := range b {
+		entries = append(entries, BagEntry[T]{Value: value, Count: count})
+	}
+	slices.SortFunc(entries, func(x, y BagEntry[T]) int { return cmp.Compare(y.Count, x.Count) })
+	if k >= 0 && k < len(entries) {
+		entries = entries[:k]
+	}
+	return entries
+}
+
+// Clone returns a copy of the bag
+func (b Bag[T]) Clone() Bag[T] {
+	if b == nil {
+		return NewBag[T]()
+	}
+	return maps.Clone(b)
+}
+
+// BagUnion returns a new bag containing all values from all bags. The count of each value is its highest count in any of the bags.
+func BagUnion[T comparable](bags ...Bag[T]) Bag[T] {
+	union := make(Bag[T])
+	for _, b := range bags {
+		for value, count := range b {
+			union[value] = max(union[value], count)
+		}
+	}
+	return union
+}
+
+// BagIntersection returns a new bag containing the values that exist in all bags. The count of each value is
+// its lowest count in any of the bags.
+func BagIntersection[T comparable](bags ...Bag[T]) Bag[T] {
+	intersection := make(Bag[T])
+	if len(bags) == 0 {
+		return intersection
+	}
+	for value, count := range bags[0] {
+		for _, b := range bags[1:] {
+			if count = min(count, b[value]); count == 0 {
+				break
+			}
+		}
+		if count > 0 {
+			intersection[value] = count
+		}
+	}
+	return intersection
+}
@@ -0,0 +1,92 @@
+package set_test
+
+import (
+	"github.com/clambin/go-common/set"
+	"maps"
+	"testing"
+)
+
+func TestBag(t *testing.T) {
+	b := set.NewBag("A", "B", "A")
+	checkEqual(t, 2, b.Count("A"))
+	checkEqual(t, 1, b.Count("B"))
+	checkEqual(t, 0, b.Count("C"))
+	checkEqual(t, 3, b.Size())
+	checkEqual(t, 2, len(b))
+
+	b.Add("C", 3)
+	b.Add("C", 0)
+	b.Add("C", -1)
+	checkEqual(t, 3, b.Count("C"))
+
+	b.Remove("C", 1)
+	checkEqual(t, 2, b.Count("C"))
+	b.Remove("C", -1)
+	checkEqual(t, 2, b.Count("C"))
+	b.Remove("C", 5)
+	checkEqual(t, 0, b.Count("C"))
+	checkEqual(t, set.New("A", "B"), b.Distinct())
+	checkEqual(t, map[string]int{"A": 2, "B": 1}, maps.Collect(b.All()))
+}
+
+func TestBag_MostCommon(t *testing.T) {
+	b := set.NewBag("A", "B", "B", "C", "C", "C")
+	tests := []struct {
+		name     string
+		k        int
+		expected []set.BagEntry[string]
+	}{
+		{name: "none", k: 0, expected: []set.BagEntry[string]{}},
+		{name: "top", k: 2, expected: []set.BagEntry[string]{{Value: "C", Count: 3}, {Value: "B", Count: 2}}},
+		{name: "all", k: -1, expected: []set.BagEntry[string]{{Value: "C", Count: 3}, {Value: "B", Count: 2}, {Value: "A", Count: 1}}},
+		{name: "too many", k: 10, expected: []set.BagEntry[string]{{Value: "C", Count: 3}, {Value: "B", Count: 2}, {Value: "A", Count: 1}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.expected, b.MostCommon(tt.k))
+		})
+	}
+}
+
+func TestBag_Clone(t *testing.T) {
+	var b set.Bag[string]
+	checkEqual(t, set.NewBag[string](), b.Clone())
+
+	b = set.NewBag("A")
+	clone := b.Clone()
+	clone.Add("A", 1)
+	checkEqual(t, 1, b.Count("A"))
+	checkEqual(t, 2, clone.Count("A"))
+}
+
+func TestBagUnion_Intersection(t *testing.T) {
+	tests := []struct {
+		name         string
+		bags         []set.Bag[string]
+		union        set.Bag[string]
+		intersection set.Bag[string]
+	}{
+		{name: "empty", union: set.Bag[string]{}, intersection: set.Bag[string]{}},
+		{name: "single", bags: []set.Bag[string]{set.NewBag("A", "A")}, union: set.Bag[string]{"A": 2}, intersection: set.Bag[string]{"A": 2}},
+		{
+			name:         "overlap",
+			bags:         []set.Bag[string]{set.NewBag("A", "A", "B"), set.NewBag("A", "B", "B", "C")},
+			union:        set.Bag[string]{"A": 2, "B": 2, "C": 1},
+			intersection: set.Bag[string]{"A": 1, "B": 1},
+		},
+		{
+			name:         "disjoint",
+			bags:         []set.Bag[string]{set.NewBag("A"), set.NewBag("B"), set.NewBag("A", "B")},
+			union:        set.Bag[string]{"A": 1, "B": 1},
+			intersection: set.Bag[string]{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.union, set.BagUnion(tt.bags...))
+			checkEqual(t, tt.intersection, set.BagIntersection(tt.bags...))
+		})
+	}
+}
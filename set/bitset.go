@@ -0,0 +1,145 @@
+package set
+
+import (
+	"iter"
+	"math/bits"
+)
+
+const wordSize = 64
+
+// Bitset holds a set of non-negative integers, using one bit per integer. For dense ranges of small integers (e.g. ports,
+// IDs), a Bitset uses far less memory than a Set[int], and set operations work on 64 values at a time.
+//
+// The zero value is an empty set, ready to use. Memory use is proportional to the largest value in the set.
+type Bitset struct {
+	words []uint64
+}
+
+// NewBitset creates a new bitset containing the optional values
+func NewBitset(values ...int) *Bitset {
+	var b Bitset
+	b.Add(values...)
+	return &b
+}
+
+// Add adds value to the set. Add panics if value is negative.
+func (b *Bitset) Add(value ...int) {
+	for _, v := range value {
+		if v < 0 {
+			panic("set: negative value added to Bitset")
+		}
+		w := v / wordSize
+		if w >= len(b.words) {
+			b.words = append(b.words, make([]uint64, w-len(b.words)+1)...)
+		}
+		b.words[w] |= 1 << (v % wordSize)
+	}
+}
+
+// Remove deletes value from the set, if present
+func (b *Bitset) Remove(value ...int) {
+	for _, v := range value {
+		if w := v / wordSize; v >= 0 && w < len(b.words) {
+			b.words[w] &^= 1 << (v % wordSize)
+		}
+	}
+}
+
+// Contains returns true if the set contains value
+func (b *Bitset) Contains(value int) bool {
+	w := value / wordSize
+	return value >= 0 && w < len(b.words) && b.words[w]&(1<<(value%wordSize)) != 0
+}
+
+// Len returns the number of values in the set
+func (b *Bitset) Len() int {
+	var count int
+	for _, w := range b.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
+}
+
+// All returns an iterator over all values in the set, in ascending order
+func (b *Bitset) All() iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i, w := range b.words {
+			for w != 0 {
+				bit := bits.TrailingZeros64(w)
+				if !yield(i*wordSize + bit) {
+					return
+				}
+				w &= w - 1
+			}
+		}
+	}
+}
+
+// List returns all values in the set, in ascending order
+func (b *Bitset) List() []int {
+	values := make([]int, 0, b.Len())
+	for value := range b.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Set returns the values as a Set
+func (b *Bitset) Set() Set[int] {
+	return Collect(b.All())
+}
+
+// Equals returns true if both sets contain the same values
+func (b *Bitset) Equals(other *Bitset) bool {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	for i := range short {
+		if short[i] != long[i] {
+			return false
+		}
+	}
+	for _, w := range long[len(short):] {
+		if w != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the set
+func (b *Bitset) Clone() *Bitset {
+	return &Bitset{words: append([]uint64(nil), b.words...)}
+}
+
+// Union returns a new set containing all values from the set and other
+func (b *Bitset) Union(other *Bitset) *Bitset {
+	short, long := b.words, other.words
+	if len(short) > len(long) {
+		short, long = long, short
+	}
+	result := Bitset{words: append([]uint64(nil), long...)}
+	for i, w := range short {
+		result.words[i] |= w
+	}
+	return &result
+}
+
+// Intersection returns a new set containing the common values between the set and other
+func (b *Bitset) Intersection(other *Bitset) *Bitset {
+	result := Bitset{words: make([]uint64, min(len(b.words), len(other.words)))}
+	for i := range result.words {
+		result.words[i] = b.words[i] & other.words[i]
+	}
+	return &result
+}
+
+// Difference returns a new set containing the values from the set that don't exist in other
+func (b *Bitset) Difference(other *Bitset) *Bitset {
+	result := b.Clone()
+	for i := range min(len(result.words), len(other.words)) {
+		result.words[i] &^= other.words[i]
+	}
+	return result
+}
@@ -0,0 +1,171 @@
+package set_test
+
+import (
+	"github.com/clambin/go-common/set"
+	"testing"
+)
+
+func TestBitset(t *testing.T) {
+	var b set.Bitset
+	checkEqual(t, 0, b.Len())
+	checkEqual(t, false, b.Contains(1))
+	checkEqual(t, false, b.Contains(-1))
+
+	b.Add(1, 64, 200, 1)
+	checkEqual(t, 3, b.Len())
+	checkEqual(t, []int{1, 64, 200}, b.List())
+	checkEqual(t, true, b.Contains(64))
+	checkEqual(t, false, b.Contains(63))
+	checkEqual(t, false, b.Contains(1000))
+
+	b.Remove(64, -1, 1000)
+	checkEqual(t, []int{1, 200}, b.List())
+	checkEqual(t, set.New(1, 200), b.Set())
+}
+
+func TestBitset_Negative(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("expected Add to panic")
+		}
+	}()
+	set.NewBitset(-1)
+}
+
+func TestBitset_Equals_Clone(t *testing.T) {
+	b := set.NewBitset(1, 2, 300)
+	clone := b.Clone()
+	checkEqual(t, true, b.Equals(clone))
+	clone.Add(4)
+	checkEqual(t, false, b.Equals(clone))
+	// trailing empty words don't matter
+	clone.Remove(4, 300)
+	b.Remove(300)
+	checkEqual(t, true, b.Equals(clone))
+	checkEqual(t, true, clone.Equals(set.NewBitset(1, 2)))
+}
+
+func TestBitset_Operations(t *testing.T) {
+	tests := []struct {
+		name         string
+		setA, setB   []int
+		union        []int
+		intersection []int
+		difference   []int
+	}{
+		{name: "empty", union: []int{}, intersection: []int{}, difference: []int{}},
+		{name: "disjoint", setA: []int{1, 100}, setB: []int{2, 300}, union: []int{1, 2, 100, 300}, intersection: []int{}, difference: []int{1, 100}},
+		{name: "overlap", setA: []int{1, 2, 300}, setB: []int{2, 300, 400}, union: []int{1, 2, 300, 400}, intersection: []int{2, 300}, difference: []int{1}},
+		{name: "longer", setA: []int{1, 500}, setB: []int{1}, union: []int{1, 500}, intersection: []int{1}, difference: []int{500}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := set.NewBitset(tt.setA...), set.NewBitset(tt.setB...)
+			checkEqual(t, tt.union, a.Union(b).List())
+			checkEqual(t, tt.union, b.Union(a).List())
+			checkEqual(t, tt.intersection, a.Intersection(b).List())
+			checkEqual(t, tt.difference, a.Difference(b).List())
+			// a is unchanged
+			checkEqual(t, set.New(tt.setA...), a.Set())
+		})
+	}
+}
+
+func TestBitset_All(t *testing.T) {
+	b := set.NewBitset(3, 1, 130)
+	var values []int
+	for value := range b.All() {
+		if values = append(values, value); len(values) == 2 {
+			break
+		}
+	}
+	checkEqual(t, []int{1, 3}, values)
+}
+
+// Compared to a Set[int] of the same range, Bitset is faster and uses less memory.
+func BenchmarkBitset_Add(b *testing.B) {
+	const setSize = 10000
+	b.ReportAllocs()
+	for b.Loop() {
+		var s set.Bitset
+		for i := range setSize {
+			s.Add(i)
+		}
+	}
+}
+
+func BenchmarkSet_Add(b *testing.B) {
+	const setSize = 10000
+	b.ReportAllocs()
+	for b.Loop() {
+		s := set.New[int]()
+		for i := range setSize {
+			s.Add(i)
+		}
+	}
+}
+
+func BenchmarkBitset_Contains(b *testing.B) {
+	const setSize = 10000
+	s := set.NewBitset()
+	for i := 0; i < setSize; i += 2 {
+		s.Add(i)
+	}
+	for b.Loop() {
+		for i := range setSize {
+			_ = s.Contains(i)
+		}
+	}
+}
+
+func BenchmarkSet_Contains(b *testing.B) {
+	const setSize = 10000
+	s := set.New[int]()
+	for i := 0; i < setSize; i += 2 {
+		s.Add(i)
+	}
+	for b.Loop() {
+		for i := range setSize {
+			_ = s.Contains(i)
+		}
+	}
+}
+
+func BenchmarkBitset_Intersection(b *testing.B) {
+	const setSize = 10000
+	s1, s2 := set.NewBitset(), set.NewBitset()
+	for i := range setSize {
+		s1.Add(i)
+		s2.Add(2 * i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = s1.Intersection(s2)
+	}
+}
+
+func BenchmarkSet_Intersection(b *testing.B) {
+	const setSize = 10000
+	s1, s2 := set.New[int](), set.New[int]()
+	for i := range setSize {
+		s1.Add(i)
+		s2.Add(2 * i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = set.Intersection(s1, s2)
+	}
+}
+
+func BenchmarkBitset_All(b *testing.B) {
+	const setSize = 10000
+	s := set.NewBitset()
+	for i := range setSize {
+		s.Add(i)
+	}
+	for b.Loop() {
+		for range s.All() {
+		}
+	}
+}
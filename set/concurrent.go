@@ -0,0 +1,114 @@
+package set
+
+import (
+	"iter"
+	"sync"
+)
+
+// Concurrent holds a set of unique values and is safe for concurrent use. The zero value is an empty set, ready to use.
+type Concurrent[T comparable] struct {
+	values Set[T]
+	lock   sync.RWMutex
+}
+
+// NewConcurrent creates a new concurrent set containing the optional values
+func NewConcurrent[T comparable](values ...T) *Concurrent[T] {
+	return &Concurrent[T]{values: New(values...)}
+}
+
+// Add adds value to the set
+func (c *Concurrent[T]) Add(value ...T) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.values == nil {
+		c.values = make(Set[T], len(value))
+	}
+	c.values.Add(value...)
+}
+
+// AddIfAbsent adds value to the set and returns true if the value wasn't already present
+func (c *Concurrent[T]) AddIfAbsent(value T) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if c.values.Contains(value) {
+		return false
+	}
+	if c.values == nil {
+		c.values = make(Set[T])
+	}
+	c.values.Add(value)
+	return true
+}
+
+// Remove deletes value from the set, if present
+func (c *Concurrent[T]) Remove(value ...T) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.values.Remove(value...)
+}
+
+// RemoveIfPresent deletes value from the set and returns true if the value was present
+func (c *Concurrent[T]) RemoveIfPresent(value T) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	if !c.values.Contains(value) {
+		return false
+	}
+	c.values.Remove(value)
+	return true
+}
+
+// Contains returns true if the set contains value
+func (c *Concurrent[T]) Contains(value T) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values.Contains(value)
+}
+
+// Len returns the number of values in the set
+func (c *Concurrent[T]) Len() int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return len(c.values)
+}
+
+// List returns all values present in the set. Order is not guaranteed. Use ListFunc if order is required.
+func (c *Concurrent[T]) List() []T {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values.List()
+}
+
+// ListFunc returns all values present in the set, sorted as determined by the cmp function
+func (c *Concurrent[T]) ListFunc(cmp func(a, b T) int) []T {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values.ListFunc(cmp)
+}
+
+// Equals returns true if both sets contain the same values
+func (c *Concurrent[T]) Equals(other *Concurrent[T]) bool {
+	// compare against a snapshot, so we never hold the lock of both sets
+	values := other.Snapshot()
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values.Equals(values)
+}
+
+// Clone returns a copy of the set
+func (c *Concurrent[T]) Clone() *Concurrent[T] {
+	return &Concurrent[T]{values: c.Snapshot()}
+}
+
+// All returns an iterator over a snapshot of the set's values. Order is not guaranteed.
+func (c *Concurrent[T]) All() iter.Seq[T] {
+	return c.Snapshot().All()
+}
+
+// Snapshot returns a copy of the set's values at the time of the call. Use it to iterate over a consistent
+// view of the set, without holding its lock.
+func (c *Concurrent[T]) Snapshot() Set[T] {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.values.Clone()
+}
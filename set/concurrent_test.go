@@ -0,0 +1,91 @@
+package set_test
+
+import (
+	"cmp"
+	"github.com/clambin/go-common/set"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrent(t *testing.T) {
+	var s set.Concurrent[string]
+	checkEqual(t, 0, s.Len())
+	checkEqual(t, false, s.Contains("A"))
+
+	s.Add("A", "B")
+	checkEqual(t, true, s.AddIfAbsent("C"))
+	checkEqual(t, false, s.AddIfAbsent("C"))
+	checkEqual(t, []string{"A", "B", "C"}, s.ListFunc(cmp.Compare[string]))
+	checkEqual(t, 3, len(s.List()))
+
+	clone := s.Clone()
+	checkEqual(t, true, s.Equals(clone))
+	checkEqual(t, true, s.Equals(&s))
+
+	s.Remove("A")
+	checkEqual(t, true, s.RemoveIfPresent("B"))
+	checkEqual(t, false, s.RemoveIfPresent("B"))
+	checkEqual(t, set.New("C"), s.Snapshot())
+	checkEqual(t, false, s.Equals(clone))
+	checkEqual(t, 3, clone.Len())
+	checkEqual(t, true, set.NewConcurrent("C").Equals(&s))
+	checkEqual(t, []string{"C"}, slices.Collect(s.All()))
+}
+
+func TestConcurrent_ZeroValue(t *testing.T) {
+	var s1, s2 set.Concurrent[int]
+	s1.Remove(1)
+	checkEqual(t, false, s1.RemoveIfPresent(1))
+	checkEqual(t, true, s1.Equals(&s2))
+	checkEqual(t, set.New[int](), s1.Snapshot())
+	checkEqual(t, true, s2.AddIfAbsent(1))
+}
+
+func FuzzConcurrent(f *testing.F) {
+	f.Add([]byte{1, 2, 3, 1, 2, 3})
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 0})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		const workers = 4
+		var s set.Concurrent[byte]
+		var added, removed atomic.Int32
+		var wg sync.WaitGroup
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, b := range data {
+					if s.AddIfAbsent(b) {
+						added.Add(1)
+					}
+					_ = s.Contains(b)
+					_ = s.List()
+					_ = s.Equals(s.Clone())
+				}
+			}()
+		}
+		wg.Wait()
+
+		// each distinct value is added exactly once
+		distinct := set.New(data...)
+		checkEqual(t, int32(len(distinct)), added.Load())
+		checkEqual(t, distinct, s.Snapshot())
+
+		for range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for _, b := range data {
+					if s.RemoveIfPresent(b) {
+						removed.Add(1)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		checkEqual(t, int32(len(distinct)), removed.Load())
+		checkEqual(t, 0, s.Len())
+	})
+}
@@ -0,0 +1,62 @@
+package set
+
+// Changes holds the differences between two sets, as determined by Diff or DiffFunc.
+type Changes[T comparable] struct {
+	// Added holds the values that only exist in the new set
+	Added Set[T]
+	// Removed holds the values that only exist in the old set
+	Removed Set[T]
+	// Unchanged holds the values that exist in both sets
+	Unchanged Set[T]
+	// Changed holds the values that exist in both sets, but have been modified. Only DiffFunc reports changed values.
+	Changed Set[T]
+}
+
+// Diff compares an old and a new set and returns the values that were added, removed or unchanged.
+func Diff[T comparable](oldSet, newSet Set[T]) Changes[T] {
+	changes := Changes[T]{Added: New[T](), Removed: New[T](), Unchanged: New[T](), Changed: New[T]()}
+	for value := range newSet {
+		if _, ok := oldSet[value]; ok {
+			changes.Unchanged[value] = struct{}{}
+		} else {
+			changes.Added[value] = struct{}{}
+		}
+	}
+	for value := range oldSet {
+		if _, ok := newSet[value]; !ok {
+			changes.Removed[value] = struct{}{}
+		}
+	}
+	return changes
+}
+
+// DiffFunc compares two sets of keyed values (e.g. structs with an ID field). Values in both sets with the same key
+// are compared with the equal function: if they are equal, the new value is reported as unchanged, otherwise as changed.
+// If equal is nil, values are compared with ==. Keys should be unique within each set.
+func DiffFunc[T comparable, K comparable](oldSet, newSet Set[T], key func(T) K, equal func(a, b T) bool) Changes[T] {
+	if equal == nil {
+		equal = func(a, b T) bool { return a == b }
+	}
+	oldByKey := make(map[K]T, len(oldSet))
+	for value := range oldSet {
+		oldByKey[key(value)] = value
+	}
+	changes := Changes[T]{Added: New[T](), Removed: New[T](), Unchanged: New[T](), Changed: New[T]()}
+	for value := range newSet {
+		k := key(value)
+		oldValue, ok := oldByKey[k]
+		switch {
+		case !ok:
+			changes.Added[value] = struct{}{}
+		case equal(oldValue, value):
+			changes.Unchanged[value] = struct{}{}
+		default:
+			changes.Changed[value] = struct{}{}
+		}
+		delete(oldByKey, k)
+	}
+	for _, value := range oldByKey {
+		changes.Removed[value] = struct{}{}
+	}
+	return changes
+}
@@ -0,0 +1,79 @@
+package set_test
+
+import (
+	"github.com/clambin/go-common/set"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name           string
+		oldSet, newSet set.Set[string]
+		added          set.Set[string]
+		removed        set.Set[string]
+		unchanged      set.Set[string]
+	}{
+		{name: "empty", added: set.New[string](), removed: set.New[string](), unchanged: set.New[string]()},
+		{name: "added", oldSet: set.New("A"), newSet: set.New("A", "B"), added: set.New("B"), removed: set.New[string](), unchanged: set.New("A")},
+		{name: "removed", oldSet: set.New("A", "B"), newSet: set.New("A"), added: set.New[string](), removed: set.New("B"), unchanged: set.New("A")},
+		{name: "mixed", oldSet: set.New("A", "B"), newSet: set.New("B", "C"), added: set.New("C"), removed: set.New("A"), unchanged: set.New("B")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := set.Diff(tt.oldSet, tt.newSet)
+			checkEqual(t, tt.added, changes.Added)
+			checkEqual(t, tt.removed, changes.Removed)
+			checkEqual(t, tt.unchanged, changes.Unchanged)
+			checkEqual(t, set.New[string](), changes.Changed)
+		})
+	}
+}
+
+func TestDiffFunc(t *testing.T) {
+	type target struct {
+		ID      string
+		Address string
+		Updated int
+	}
+	key := func(t target) string { return t.ID }
+	oldSet := set.New(
+		target{ID: "a", Address: "10.0.0.1", Updated: 1},
+		target{ID: "b", Address: "10.0.0.2", Updated: 1},
+		target{ID: "c", Address: "10.0.0.3", Updated: 1},
+	)
+	newSet := set.New(
+		target{ID: "a", Address: "10.0.0.1", Updated: 2},
+		target{ID: "b", Address: "10.0.0.20", Updated: 2},
+		target{ID: "d", Address: "10.0.0.4", Updated: 2},
+	)
+
+	tests := []struct {
+		name      string
+		equal     func(a, b target) bool
+		unchanged set.Set[target]
+		changed   set.Set[target]
+	}{
+		{
+			name:      "default",
+			unchanged: set.New[target](),
+			changed:   set.New(target{ID: "a", Address: "10.0.0.1", Updated: 2}, target{ID: "b", Address: "10.0.0.20", Updated: 2}),
+		},
+		{
+			name:      "custom",
+			equal:     func(a, b target) bool { return a.Address == b.Address },
+			unchanged: set.New(target{ID: "a", Address: "10.0.0.1", Updated: 2}),
+			changed:   set.New(target{ID: "b", Address: "10.0.0.20", Updated: 2}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes := set.DiffFunc(oldSet, newSet, key, tt.equal)
+			checkEqual(t, set.New(target{ID: "d", Address: "10.0.0.4", Updated: 2}), changes.Added)
+			checkEqual(t, set.New(target{ID: "c", Address: "10.0.0.3", Updated: 1}), changes.Removed)
+			checkEqual(t, tt.unchanged, changes.Unchanged)
+			checkEqual(t, tt.changed, changes.Changed)
+		})
+	}
+}
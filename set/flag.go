@@ -0,0 +1,81 @@
+package set
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+)
+
+var _ flag.Value = &FlagValue[string]{}
+
+// FlagValue parses a command-line flag into a Set. It implements flag.Value and pflag.Value, so it can be registered
+// with the standard flag package or with cobra, e.g.:
+//
+//	labels := set.New("foo")
+//	cmd.Flags().Var(set.NewFlagValue(&labels), "labels", "comma-separated list of labels")
+//
+// The flag accepts a comma-separated list of values and can be repeated. The first occurrence replaces the set's
+// initial (i.e. default) values, subsequent ones add to it.
+type FlagValue[T comparable] struct {
+	s       *Set[T]
+	changed bool
+}
+
+// NewFlagValue returns a FlagValue that parses the flag into s
+func NewFlagValue[T comparable](s *Set[T]) *FlagValue[T] {
+	if *s == nil {
+		*s = New[T]()
+	}
+	return &FlagValue[T]{s: s}
+}
+
+// String returns the values in the set as a comma-separated list, sorted by value
+func (f *FlagValue[T]) String() string {
+	if f == nil || f.s == nil {
+		return ""
+	}
+	values := make([]string, 0, len(*f.s))
+	for _, value := range sortValues(f.s.List()) {
+		text, _ := valueToText(value)
+		values = append(values, text)
+	}
+	return strings.Join(values, ",")
+}
+
+// Set parses a comma-separated list of values and adds them to the set
+func (f *FlagValue[T]) Set(text string) error {
+	var values Set[T]
+	if err := values.decodeText([]byte(text), false); err != nil {
+		return err
+	}
+	if !f.changed {
+		*f.s = New[T]()
+		f.changed = true
+	}
+	f.s.Add(values.List()...)
+	return nil
+}
+
+// Type returns the type of the flag. For sets of strings and numbers, this is the type of the equivalent pflag slice flag
+// (e.g. "stringSlice" for a Set[string]), so that cobra's help output treats the flag as a list. viper converts
+// stringSlice and intSlice flags to a slice. It returns any other flag as a comma-separated string, which DecodeHook decodes.
+func (f *FlagValue[T]) Type() string {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.String:
+		return "stringSlice"
+	case reflect.Int:
+		return "intSlice"
+	case reflect.Int32:
+		return "int32Slice"
+	case reflect.Int64:
+		return "int64Slice"
+	case reflect.Uint:
+		return "uintSlice"
+	case reflect.Float32:
+		return "float32Slice"
+	case reflect.Float64:
+		return "float64Slice"
+	default:
+		return "valueSlice"
+	}
+}
@@ -0,0 +1,64 @@
+package set_test
+
+import (
+	"flag"
+	"github.com/clambin/go-common/set"
+	"io"
+	"net/netip"
+	"testing"
+)
+
+func TestFlagValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected set.Set[string]
+	}{
+		{name: "default", expected: set.New("foo")},
+		{name: "replace default", args: []string{"-labels", "a,b"}, expected: set.New("a", "b")},
+		{name: "repeated", args: []string{"-labels", "a,b", "-labels", "b, c"}, expected: set.New("a", "b", "c")},
+		{name: "empty", args: []string{"-labels", ""}, expected: set.New[string]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels := set.New("foo")
+			f := flag.NewFlagSet("test", flag.ContinueOnError)
+			f.SetOutput(io.Discard)
+			f.Var(set.NewFlagValue(&labels), "labels", "labels")
+			if err := f.Parse(tt.args); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkEqual(t, tt.expected, labels)
+		})
+	}
+}
+
+func TestFlagValue_Numeric(t *testing.T) {
+	var ports set.Set[int]
+	f := flag.NewFlagSet("test", flag.ContinueOnError)
+	f.SetOutput(io.Discard)
+	v := set.NewFlagValue(&ports)
+	f.Var(v, "ports", "ports")
+
+	if err := f.Parse([]string{"-ports", "443,80"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, set.New(80, 443), ports)
+	checkEqual(t, "80,443", v.String())
+	checkEqual(t, "intSlice", v.Type())
+
+	if err := f.Parse([]string{"-ports", "foo"}); err == nil {
+		t.Error("expected error, got none")
+	}
+}
+
+func TestFlagValue_Type(t *testing.T) {
+	type ID string
+	checkEqual(t, "stringSlice", (&set.FlagValue[string]{}).Type())
+	checkEqual(t, "stringSlice", (&set.FlagValue[ID]{}).Type())
+	checkEqual(t, "intSlice", (&set.FlagValue[int]{}).Type())
+	checkEqual(t, "float64Slice", (&set.FlagValue[float64]{}).Type())
+	checkEqual(t, "valueSlice", (&set.FlagValue[netip.Addr]{}).Type())
+	checkEqual(t, "", (&set.FlagValue[string]{}).String())
+}
@@ -0,0 +1,257 @@
+package set
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	hamtBits = 5
+	hamtMask = 1<<hamtBits - 1
+)
+
+var hamtSeed = maphash.MakeSeed()
+
+// Immutable holds a set of unique values that can't be modified. Instead, With and Without return a new version
+// of the set, which shares most of its structure with the original one. Creating a new version is therefore O(log n),
+// compared to O(n) for cloning a Set.
+//
+// Since an Immutable set never changes, it is safe for concurrent use without locking, and can be passed between
+// goroutines as a snapshot. The zero value is an empty set, ready to use.
+//
+// Immutable is implemented as a hash array mapped trie (HAMT).
+type Immutable[T comparable] struct {
+	root *hamtNode[T]
+	len  int
+}
+
+// hamtNode is a node in the trie. Each level uses hamtBits of the value's hash to select one of 32 positions.
+// Only the occupied positions are stored in entries, in order. bitmap records which positions are occupied.
+type hamtNode[T comparable] struct {
+	bitmap  uint32
+	entries []hamtEntry[T]
+}
+
+// hamtEntry is either a sub-node or a leaf. A leaf holds the values with the same hash: more than one value
+// means the hashes collide.
+type hamtEntry[T comparable] struct {
+	node   *hamtNode[T]
+	hash   uint64
+	values []T
+}
+
+// NewImmutable creates a new immutable set containing the optional values
+func NewImmutable[T comparable](values ...T) Immutable[T] {
+	var s Immutable[T]
+	for _, value := range values {
+		s = s.With(value)
+	}
+	return s
+}
+
+// With returns a set that contains the values of the set, plus value
+func (s Immutable[T]) With(value T) Immutable[T] {
+	root := s.root
+	if root == nil {
+		root = &hamtNode[T]{}
+	}
+	root, added := root.with(maphash.Comparable(hamtSeed, value), 0, value)
+	if !added {
+		return s
+	}
+	return Immutable[T]{root: root, len: s.len + 1}
+}
+
+// Without returns a set that contains the values of the set, except value
+func (s Immutable[T]) Without(value T) Immutable[T] {
+	if s.root == nil {
+		return s
+	}
+	root, removed := s.root.without(maphash.Comparable(hamtSeed, value), 0, value)
+	if !removed {
+		return s
+	}
+	return Immutable[T]{root: root, len: s.len - 1}
+}
+
+// Contains returns true if the set contains value
+func (s Immutable[T]) Contains(value T) bool {
+	return s.root.contains(maphash.Comparable(hamtSeed, value), value)
+}
+
+// Len returns the number of values in the set
+func (s Immutable[T]) Len() int {
+	return s.len
+}
+
+// All returns an iterator over all values in the set. Order is not guaranteed.
+func (s Immutable[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if s.root != nil {
+			s.root.all(yield)
+		}
+	}
+}
+
+// List returns all values present in the set. Order is not guaranteed.
+func (s Immutable[T]) List() []T {
+	values := make([]T, 0, s.len)
+	for value := range s.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Set returns the values as a Set
+func (s Immutable[T]) Set() Set[T] {
+	return Collect(s.All())
+}
+
+// Equals returns true if both sets contain the same values
+func (s Immutable[T]) Equals(other Immutable[T]) bool {
+	if s.len != other.len {
+		return false
+	}
+	for value := range s.All() {
+		if !other.Contains(value) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *hamtNode[T]) bit(hash uint64, shift uint) uint32 {
+	return 1 << ((hash >> shift) & hamtMask)
+}
+
+func (n *hamtNode[T]) index(bit uint32) int {
+	return bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[T]) contains(hash uint64, value T) bool {
+	for shift := uint(0); n != nil; shift += hamtBits {
+		bit := n.bit(hash, shift)
+		if n.bitmap&bit == 0 {
+			return false
+		}
+		e := &n.entries[n.index(bit)]
+		if e.node == nil {
+			return e.hash == hash && slices.Contains(e.values, value)
+		}
+		n = e.node
+	}
+	return false
+}
+
+func (n *hamtNode[T]) with(hash uint64, shift uint, value T) (*hamtNode[T], bool) {
+	bit := n.bit(hash, shift)
+	i := n.index(bit)
+	if n.bitmap&bit == 0 {
+		entries := make([]hamtEntry[T], 0, len(n.entries)+1)
+		entries = append(entries, n.entries[:i]...)
+		entries = append(entries, hamtEntry[T]{hash: hash, values: []T{value}})
+		entries = append(entries, n.entries[i:]...)
+		return &hamtNode[T]{bitmap: n.bitmap | bit, entries: entries}, true
+	}
+
+	var entry hamtEntry[T]
+	switch e := n.entries[i]; {
+	case e.node != nil:
+		child, added := e.node.with(hash, shift+hamtBits, value)
+		if !added {
+			return n, false
+		}
+		entry = hamtEntry[T]{node: child}
+	case e.hash == hash:
+		if slices.Contains(e.values, value) {
+			return n, false
+		}
+		entry = hamtEntry[T]{hash: hash, values: append(slices.Clip(e.values), value)}
+	default:
+		entry = hamtEntry[T]{node: mergeLeaves(e, hamtEntry[T]{hash: hash, values: []T{value}}, shift+hamtBits)}
+	}
+	return n.replace(i, entry), true
+}
+
+func (n *hamtNode[T]) without(hash uint64, shift uint, value T) (*hamtNode[T], bool) {
+	bit := n.bit(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	i := n.index(bit)
+
+	var entry hamtEntry[T]
+	switch e := n.entries[i]; {
+	case e.node != nil:
+		child, removed := e.node.without(hash, shift+hamtBits, value)
+		switch {
+		case !removed:
+			return n, false
+		case child == nil:
+			return n.remove(i, bit), true
+		case len(child.entries) == 1 && child.entries[0].node == nil:
+			// a single leaf doesn't need its own node
+			entry = child.entries[0]
+		default:
+			entry = hamtEntry[T]{node: child}
+		}
+	case e.hash == hash && slices.Contains(e.values, value):
+		if len(e.values) == 1 {
+			return n.remove(i, bit), true
+		}
+		entry = hamtEntry[T]{hash: hash, values: slices.DeleteFunc(slices.Clone(e.values), func(v T) bool { return v == value })}
+	default:
+		return n, false
+	}
+	return n.replace(i, entry), true
+}
+
+// replace returns a copy of the node, with the entry at index i replaced
+func (n *hamtNode[T]) replace(i int, entry hamtEntry[T]) *hamtNode[T] {
+	entries := slices.Clone(n.entries)
+	entries[i] = entry
+	return &hamtNode[T]{bitmap: n.bitmap, entries: entries}
+}
+
+// remove returns a copy of the node, without the entry at index i. If the node would be empty, remove returns nil.
+func (n *hamtNode[T]) remove(i int, bit uint32) *hamtNode[T] {
+	if len(n.entries) == 1 {
+		return nil
+	}
+	entries := make([]hamtEntry[T], 0, len(n.entries)-1)
+	entries = append(entries, n.entries[:i]...)
+	entries = append(entries, n.entries[i+1:]...)
+	return &hamtNode[T]{bitmap: n.bitmap &^ bit, entries: entries}
+}
+
+func (n *hamtNode[T]) all(yield func(T) bool) bool {
+	for _, e := range n.entries {
+		if e.node != nil {
+			if !e.node.all(yield) {
+				return false
+			}
+			continue
+		}
+		for _, value := range e.values {
+			if !yield(value) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// mergeLeaves returns a node holding two leaves with different hashes, adding levels until their hashes diverge.
+func mergeLeaves[T comparable](a, b hamtEntry[T], shift uint) *hamtNode[T] {
+	var n hamtNode[T]
+	bitA, bitB := n.bit(a.hash, shift), n.bit(b.hash, shift)
+	if bitA == bitB {
+		return &hamtNode[T]{bitmap: bitA, entries: []hamtEntry[T]{{node: mergeLeaves(a, b, shift+hamtBits)}}}
+	}
+	if bitA > bitB {
+		a, b = b, a
+	}
+	return &hamtNode[T]{bitmap: bitA | bitB, entries: []hamtEntry[T]{a, b}}
+}
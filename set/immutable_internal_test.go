@@ -0,0 +1,93 @@
+package set
+
+import (
+	"slices"
+	"testing"
+)
+
+// The hash of a value is random, so the tests below use the trie directly, with chosen hashes, to cover
+// colliding hashes and hashes that share a long prefix.
+
+func TestHamtNode_Collisions(t *testing.T) {
+	const (
+		collision = 7
+		prefix    = collision | 1<<62 // shares all but the last level with collision
+		other     = 8
+	)
+	hashes := map[string]uint64{"a": collision, "b": collision, "c": collision, "d": prefix, "e": other}
+
+	root := &hamtNode[string]{}
+	for _, value := range []string{"a", "b", "c", "d", "e"} {
+		var added bool
+		if root, added = root.with(hashes[value], 0, value); !added {
+			t.Fatalf("%s: not added", value)
+		}
+	}
+	if _, added := root.with(collision, 0, "b"); added {
+		t.Fatal("duplicate value added")
+	}
+	for value, hash := range hashes {
+		if !root.contains(hash, value) {
+			t.Errorf("%s: not found", value)
+		}
+	}
+	// same hash, different value
+	if root.contains(collision, "x") {
+		t.Error("unexpected value found")
+	}
+	checkValues(t, root, "a", "b", "c", "d", "e")
+
+	// remove a value from a leaf with multiple values, without changing the original trie
+	removed, ok := root.without(collision, 0, "b")
+	if !ok {
+		t.Fatal("b: not removed")
+	}
+	if removed.contains(collision, "b") || !removed.contains(collision, "a") || !removed.contains(collision, "c") {
+		t.Error("unexpected content after removing b")
+	}
+	checkValues(t, removed, "a", "c", "d", "e")
+	checkValues(t, root, "a", "b", "c", "d", "e")
+
+	if _, ok = removed.without(collision, 0, "b"); ok {
+		t.Error("b removed twice")
+	}
+
+	// removing all values empties the trie
+	n := removed
+	for _, value := range []string{"d", "a", "c", "e"} {
+		if n, ok = n.without(hashes[value], 0, value); !ok {
+			t.Fatalf("%s: not removed", value)
+		}
+	}
+	if n != nil {
+		t.Errorf("expected empty trie, got %v", n.entries)
+	}
+}
+
+func TestHamtNode_Collapse(t *testing.T) {
+	// two hashes that share the first level, so they're stored in a sub-node
+	a, b := uint64(1), uint64(1|1<<hamtBits)
+	root, _ := (&hamtNode[string]{}).with(a, 0, "a")
+	root, _ = root.with(b, 0, "b")
+	if len(root.entries) != 1 || root.entries[0].node == nil {
+		t.Fatal("expected a sub-node")
+	}
+	root, _ = root.without(b, 0, "b")
+	// the remaining leaf moves up to the root
+	if len(root.entries) != 1 || root.entries[0].node != nil || !root.contains(a, "a") {
+		t.Error("expected the sub-node to be collapsed")
+	}
+}
+
+func checkValues(t *testing.T, n *hamtNode[string], expected ...string) {
+	t.Helper()
+	var values []string
+	n.all(func(value string) bool {
+		values = append(values, value)
+		return true
+	})
+	slices.Sort(values)
+	if !slices.Equal(values, expected) {
+		t.Errorf("expected %v, got %v", expected, values)
+	}
+}
@@ -0,0 +1,106 @@
+package set_test
+
+import (
+	"github.com/clambin/go-common/set"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestImmutable(t *testing.T) {
+	var empty set.Immutable[string]
+	checkEqual(t, 0, empty.Len())
+	checkEqual(t, false, empty.Contains("A"))
+	checkEqual(t, 0, empty.Without("A").Len())
+
+	s1 := empty.With("A").With("B")
+	s2 := s1.With("C").Without("A")
+	s3 := s2.With("C")
+
+	checkEqual(t, set.New[string](), empty.Set())
+	checkEqual(t, set.New("A", "B"), s1.Set())
+	checkEqual(t, set.New("B", "C"), s2.Set())
+	checkEqual(t, 2, s2.Len())
+	checkEqual(t, true, s2.Contains("C"))
+	checkEqual(t, false, s2.Contains("A"))
+	checkEqual(t, true, s2.Equals(s3))
+	checkEqual(t, false, s1.Equals(s2))
+	checkEqual(t, 2, len(s2.List()))
+}
+
+func TestImmutable_Random(t *testing.T) {
+	// keep every version, along with its expected content, to check that older versions don't change
+	var versions []set.Immutable[int]
+	var expected []set.Set[int]
+	s := set.NewImmutable[int]()
+	reference := set.New[int]()
+	for i := range 10000 {
+		v := rand.IntN(2000)
+		if rand.IntN(3) == 0 {
+			s = s.Without(v)
+			reference.Remove(v)
+		} else {
+			s = s.With(v)
+			reference.Add(v)
+		}
+		if i%1000 == 0 {
+			versions = append(versions, s)
+			expected = append(expected, reference.Clone())
+		}
+	}
+	versions = append(versions, s)
+	expected = append(expected, reference)
+
+	for i := range versions {
+		checkEqual(t, len(expected[i]), versions[i].Len())
+		checkEqual(t, expected[i], versions[i].Set())
+		for v := range 2000 {
+			if versions[i].Contains(v) != expected[i].Contains(v) {
+				t.Fatalf("version %d: Contains(%d): expected %v", i, v, expected[i].Contains(v))
+			}
+		}
+	}
+
+	// removing all values results in an empty set
+	for v := range s.All() {
+		s = s.Without(v)
+	}
+	checkEqual(t, 0, s.Len())
+	checkEqual(t, set.New[int](), s.Set())
+}
+
+func TestImmutable_All(t *testing.T) {
+	s := set.NewImmutable(1, 2, 3, 4)
+	var count int
+	for range s.All() {
+		if count++; count == 2 {
+			break
+		}
+	}
+	checkEqual(t, 2, count)
+}
+
+// Compared to cloning a Set, With only copies the path to the new value.
+func BenchmarkImmutable_With(b *testing.B) {
+	const setSize = 10000
+	s := set.NewImmutable[int]()
+	for i := range setSize {
+		s = s.With(i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = s.With(setSize)
+	}
+}
+
+func BenchmarkSet_Clone_Add(b *testing.B) {
+	const setSize = 10000
+	s := set.New[int]()
+	for i := range setSize {
+		s.Add(i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		c := s.Clone()
+		c.Add(setSize)
+	}
+}
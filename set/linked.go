@@ -0,0 +1,145 @@
+package set
+
+import "iter"
+
+// LinkedSet holds a set of unique values, in the order they were added. Adding, removing and finding a value is O(1).
+// Adding a value that is already in the set doesn't change its position.
+//
+// The zero value is an empty set, ready to use. The set must not be modified while iterating over it.
+type LinkedSet[T comparable] struct {
+	entries map[T]*linkedEntry[T]
+	head    *linkedEntry[T]
+	tail    *linkedEntry[T]
+}
+
+type linkedEntry[T comparable] struct {
+	value      T
+	prev, next *linkedEntry[T]
+}
+
+// NewLinked creates a new insertion-ordered set containing the optional values
+func NewLinked[T comparable](values ...T) *LinkedSet[T] {
+	var s LinkedSet[T]
+	s.Add(values...)
+	return &s
+}
+
+// CollectLinked creates a new insertion-ordered set containing the values of the iterator, in order.
+// Use CollectLinked(s.All()) to convert a Set, or CollectLinked(Sorted(s)) to get a deterministic order.
+func CollectLinked[T comparable](seq iter.Seq[T]) *LinkedSet[T] {
+	var s LinkedSet[T]
+	for value := range seq {
+		s.Add(value)
+	}
+	return &s
+}
+
+// Add adds value to the end of the set, unless the set already contains it
+func (s *LinkedSet[T]) Add(value ...T) {
+	if s.entries == nil {
+		s.entries = make(map[T]*linkedEntry[T], len(value))
+	}
+	for i := range value {
+		if _, ok := s.entries[value[i]]; ok {
+			continue
+		}
+		e := &linkedEntry[T]{value: value[i], prev: s.tail}
+		if s.tail != nil {
+			s.tail.next = e
+		} else {
+			s.head = e
+		}
+		s.tail = e
+		s.entries[value[i]] = e
+	}
+}
+
+// Remove deletes value from the set, if present
+func (s *LinkedSet[T]) Remove(value ...T) {
+	for i := range value {
+		e, ok := s.entries[value[i]]
+		if !ok {
+			continue
+		}
+		if e.prev != nil {
+			e.prev.next = e.next
+		} else {
+			s.head = e.next
+		}
+		if e.next != nil {
+			e.next.prev = e.prev
+		} else {
+			s.tail = e.prev
+		}
+		delete(s.entries, value[i])
+	}
+}
+
+// Contains returns true if the set contains value
+func (s *LinkedSet[T]) Contains(value T) bool {
+	_, ok := s.entries[value]
+	return ok
+}
+
+// Len returns the number of values in the set
+func (s *LinkedSet[T]) Len() int {
+	return len(s.entries)
+}
+
+// All returns an iterator over all values in the set, in the order they were added
+func (s *LinkedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := s.head; e != nil; e = e.next {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over all values in the set, in reverse order
+func (s *LinkedSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := s.tail; e != nil; e = e.prev {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
+
+// List returns all values in the set, in the order they were added
+func (s *LinkedSet[T]) List() []T {
+	values := make([]T, 0, len(s.entries))
+	for value := range s.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Set returns the values as a Set
+func (s *LinkedSet[T]) Set() Set[T] {
+	result := make(Set[T], len(s.entries))
+	for value := range s.entries {
+		result.Add(value)
+	}
+	return result
+}
+
+// Equals returns true if both sets contain the same values, in the same order
+func (s *LinkedSet[T]) Equals(other *LinkedSet[T]) bool {
+	if s.Len() != other.Len() {
+		return false
+	}
+	for e, o := s.head, other.head; e != nil; e, o = e.next, o.next {
+		if e.value != o.value {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the set
+func (s *LinkedSet[T]) Clone() *LinkedSet[T] {
+	return CollectLinked(s.All())
+}
@@ -0,0 +1,65 @@
+package set_test
+
+import (
+	"github.com/clambin/go-common/set"
+	"slices"
+	"testing"
+)
+
+func TestLinkedSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		add      []string
+		remove   []string
+		expected []string
+	}{
+		{name: "empty", expected: []string{}},
+		{name: "insertion order", add: []string{"C", "A", "B"}, expected: []string{"C", "A", "B"}},
+		{name: "duplicates", add: []string{"C", "A", "C", "B", "A"}, expected: []string{"C", "A", "B"}},
+		{name: "remove first", add: []string{"C", "A", "B"}, remove: []string{"C"}, expected: []string{"A", "B"}},
+		{name: "remove middle", add: []string{"C", "A", "B"}, remove: []string{"A"}, expected: []string{"C", "B"}},
+		{name: "remove last", add: []string{"C", "A", "B"}, remove: []string{"B"}, expected: []string{"C", "A"}},
+		{name: "remove all", add: []string{"C", "A", "B"}, remove: []string{"A", "B", "C", "D"}, expected: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var s set.LinkedSet[string]
+			s.Add(tt.add...)
+			s.Remove(tt.remove...)
+			checkEqual(t, tt.expected, s.List())
+			checkEqual(t, len(tt.expected), s.Len())
+			backward := slices.Clone(tt.expected)
+			slices.Reverse(backward)
+			checkEqual(t, backward, append([]string{}, slices.Collect(s.Backward())...))
+			for _, value := range tt.expected {
+				checkEqual(t, true, s.Contains(value))
+			}
+		})
+	}
+}
+
+func TestLinkedSet_ReAdd(t *testing.T) {
+	s := set.NewLinked("A", "B", "C")
+	s.Remove("A")
+	s.Add("A")
+	checkEqual(t, []string{"B", "C", "A"}, s.List())
+}
+
+func TestLinkedSet_Set(t *testing.T) {
+	s := set.NewLinked("B", "A")
+	checkEqual(t, set.New("A", "B"), s.Set())
+
+	s = set.CollectLinked(set.Sorted(set.New("B", "C", "A")))
+	checkEqual(t, []string{"A", "B", "C"}, s.List())
+}
+
+func TestLinkedSet_Equals_Clone(t *testing.T) {
+	s := set.NewLinked("A", "B")
+	clone := s.Clone()
+	checkEqual(t, true, s.Equals(clone))
+	checkEqual(t, false, s.Equals(set.NewLinked("B", "A")))
+	clone.Add("C")
+	checkEqual(t, false, s.Equals(clone))
+	checkEqual(t, []string{"A", "B"}, s.List())
+}
@@ -0,0 +1,234 @@
+package set
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrDuplicate is returned when decoding a list that contains the same value more than once, using Strict.
+var ErrDuplicate = errors.New("duplicate value")
+
+var (
+	_ json.Marshaler           = Set[string]{}
+	_ json.Unmarshaler         = &Set[string]{}
+	_ encoding.TextMarshaler   = Set[string]{}
+	_ encoding.TextUnmarshaler = &Set[string]{}
+)
+
+// MarshalJSON encodes the set as a sorted JSON array
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(sortValues(s.List()))
+}
+
+// UnmarshalJSON decodes a JSON array into the set. Any values already in the set are removed.
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	return s.decodeJSON(data, false)
+}
+
+// MarshalYAML encodes the set as a sorted YAML sequence
+func (s Set[T]) MarshalYAML() (any, error) {
+	return sortValues(s.List()), nil
+}
+
+// UnmarshalYAML decodes a YAML sequence into the set. Any values already in the set are removed.
+func (s *Set[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	return s.decodeYAML(unmarshal, false)
+}
+
+// MarshalText encodes the set as a sorted, comma-separated list. Values should therefore not contain a comma.
+func (s Set[T]) MarshalText() ([]byte, error) {
+	values := make([]string, 0, len(s))
+	for _, value := range sortValues(s.List()) {
+		text, err := valueToText(value)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, text)
+	}
+	return []byte(strings.Join(values, ",")), nil
+}
+
+// UnmarshalText decodes a comma-separated list into the set. Any values already in the set are removed.
+func (s *Set[T]) UnmarshalText(text []byte) error {
+	return s.decodeText(text, false)
+}
+
+// Strict returns a decoder that decodes into the set, but rejects any input containing duplicate values.
+// The decoder supports JSON, YAML and text, e.g.:
+//
+//	var s set.Set[string]
+//	err := json.Unmarshal(data, set.Strict(&s))
+func Strict[T comparable](s *Set[T]) interface {
+	json.Unmarshaler
+	encoding.TextUnmarshaler
+	UnmarshalYAML(func(any) error) error
+} {
+	return &strict[T]{s: s}
+}
+
+type strict[T comparable] struct {
+	s *Set[T]
+}
+
+func (d strict[T]) UnmarshalJSON(data []byte) error {
+	return d.s.decodeJSON(data, true)
+}
+
+func (d strict[T]) UnmarshalYAML(unmarshal func(any) error) error {
+	return d.s.decodeYAML(unmarshal, true)
+}
+
+func (d strict[T]) UnmarshalText(text []byte) error {
+	return d.s.decodeText(text, true)
+}
+
+func (s *Set[T]) decodeJSON(data []byte, strict bool) error {
+	var values []T
+	if err := json.Unmarshal(data, &values); err != nil {
+		return err
+	}
+	return s.fromSlice(values, strict)
+}
+
+func (s *Set[T]) decodeYAML(unmarshal func(any) error, strict bool) error {
+	var values []T
+	if err := unmarshal(&values); err != nil {
+		return err
+	}
+	return s.fromSlice(values, strict)
+}
+
+func (s *Set[T]) decodeText(text []byte, strict bool) error {
+	var values []T
+	for field := range strings.SplitSeq(string(text), ",") {
+		if field = strings.TrimSpace(field); field == "" {
+			continue
+		}
+		value, err := textToValue[T](field)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+	return s.fromSlice(values, strict)
+}
+
+func (s *Set[T]) fromSlice(values []T, strict bool) error {
+	result := make(Set[T], len(values))
+	for _, value := range values {
+		if strict && result.Contains(value) {
+			return fmt.Errorf("%w: %v", ErrDuplicate, value)
+		}
+		result.Add(value)
+	}
+	*s = result
+	return nil
+}
+
+// valueToText returns the text representation of a value: strings are used as-is, types implementing
+// encoding.TextMarshaler use their text representation and all other types use their JSON representation.
+func valueToText[T any](value T) (string, error) {
+	if m, ok := any(value).(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		return string(text), err
+	}
+	if v := reflect.ValueOf(value); v.Kind() == reflect.String {
+		return v.String(), nil
+	}
+	text, err := json.Marshal(value)
+	return string(text), err
+}
+
+// textToValue is the inverse of valueToText.
+func textToValue[T any](text string) (T, error) {
+	var value T
+	if u, ok := any(&value).(encoding.TextUnmarshaler); ok {
+		return value, u.UnmarshalText([]byte(text))
+	}
+	if v := reflect.ValueOf(&value).Elem(); v.Kind() == reflect.String {
+		v.SetString(text)
+		return value, nil
+	}
+	err := json.Unmarshal([]byte(text), &value)
+	return value, err
+}
+
+// sortValues sorts values with compareValues, so that the encoding of a set is deterministic.
+func sortValues[T any](values []T) []T {
+	slices.SortFunc(values, compareValues[T])
+	return values
+}
+
+// ordered returns true if T is a number or a string, i.e. a type that compareValues orders by value.
+func ordered[T any]() bool {
+	switch reflect.TypeFor[T]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return true
+	default:
+		return false
+	}
+}
+
+// compareValues compares values of a basic type by their value, and values of any other type by their JSON representation.
+func compareValues[T any](a, b T) int {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(va.Float(), vb.Float())
+	case reflect.String:
+		return cmp.Compare(va.String(), vb.String())
+	default:
+		ja, _ := json.Marshal(a)
+		jb, _ := json.Marshal(b)
+		return bytes.Compare(ja, jb)
+	}
+}
+
+// DecodeHook is a mapstructure decode hook that decodes a list, or a comma-separated string, into a Set.
+// This allows viper to load sets from its configuration, e.g.:
+//
+//	err := v.Unmarshal(&cfg, viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+//		set.DecodeHook,
+//		mapstructure.StringToTimeDurationHookFunc(),
+//	)))
+func DecodeHook(_ reflect.Type, to reflect.Type, data any) (any, error) {
+	if to.Kind() != reflect.Map || !reflect.PointerTo(to).Implements(reflect.TypeFor[decoder]()) {
+		return data, nil
+	}
+	s := reflect.New(to)
+	d := s.Interface().(decoder)
+	var err error
+	switch data := data.(type) {
+	case string:
+		err = d.decodeText([]byte(data), false)
+	default:
+		var encoded []byte
+		if encoded, err = json.Marshal(data); err == nil {
+			err = d.decodeJSON(encoded, false)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.Elem().Interface(), nil
+}
+
+// decoder is implemented by all sets. DecodeHook uses it to recognize a Set, regardless of its type parameter.
+type decoder interface {
+	decodeJSON([]byte, bool) error
+	decodeText([]byte, bool) error
+}
@@ -0,0 +1,133 @@
+package set_test
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/clambin/go-common/set"
+	"gopkg.in/yaml.v3"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestSet_JSON(t *testing.T) {
+	type config struct {
+		Names set.Set[string] `json:"names"`
+		Ports set.Set[int]    `json:"ports"`
+	}
+	cfg := config{Names: set.New("b", "a"), Ports: set.New(10, 9, 100)}
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, `{"names":["a","b"],"ports":[9,10,100]}`, string(data))
+
+	var got config
+	if err = json.Unmarshal([]byte(`{"names":["a","b","a"],"ports":[9,10,100]}`), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, cfg, got)
+
+	if err = json.Unmarshal([]byte(`{"names":"a"}`), &got); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestSet_YAML(t *testing.T) {
+	type config struct {
+		Names set.Set[string] `yaml:"names"`
+	}
+	cfg := config{Names: set.New("b", "a")}
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, "names:\n    - a\n    - b\n", string(data))
+
+	var got config
+	if err = yaml.Unmarshal([]byte("names: [a, b, a]"), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, cfg, got)
+}
+
+func TestSet_Text(t *testing.T) {
+	tests := []struct {
+		name string
+		set  any
+		text string
+	}{
+		{name: "strings", set: set.New("b", "a"), text: "a,b"},
+		{name: "numbers", set: set.New(2, 1), text: "1,2"},
+		{name: "numeric order", set: set.New(2, 10, 3), text: "2,3,10"},
+		{name: "text marshaler", set: set.New(netip.MustParseAddr("10.0.0.2"), netip.MustParseAddr("10.0.0.1")), text: "10.0.0.1,10.0.0.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text, err := tt.set.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkEqual(t, tt.text, string(text))
+
+			got := reflect.New(reflect.TypeOf(tt.set))
+			if err = got.Interface().(interface{ UnmarshalText([]byte) error }).UnmarshalText([]byte(" " + tt.text + ", ")); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			checkEqual(t, tt.set, got.Elem().Interface())
+		})
+	}
+
+	var s set.Set[int]
+	if err := s.UnmarshalText([]byte("1,a")); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestStrict(t *testing.T) {
+	var s set.Set[string]
+	if err := json.Unmarshal([]byte(`["a","b"]`), set.Strict(&s)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	checkEqual(t, set.New("a", "b"), s)
+
+	if err := json.Unmarshal([]byte(`["a","a"]`), set.Strict(&s)); !errors.Is(err, set.ErrDuplicate) {
+		t.Errorf("JSON: got %v, want %v", err, set.ErrDuplicate)
+	}
+	if err := yaml.Unmarshal([]byte(`[a, a]`), set.Strict(&s)); !errors.Is(err, set.ErrDuplicate) {
+		t.Errorf("YAML: got %v, want %v", err, set.ErrDuplicate)
+	}
+	if err := set.Strict(&s).UnmarshalText([]byte(`a,a`)); !errors.Is(err, set.ErrDuplicate) {
+		t.Errorf("text: got %v, want %v", err, set.ErrDuplicate)
+	}
+}
+
+func TestDecodeHook(t *testing.T) {
+	to := reflect.TypeFor[set.Set[string]]()
+	tests := []struct {
+		name    string
+		to      reflect.Type
+		data    any
+		want    any
+		wantErr bool
+	}{
+		{name: "list", to: to, data: []any{"a", "b", "a"}, want: set.New("a", "b")},
+		{name: "string", to: to, data: "a,b", want: set.New("a", "b")},
+		{name: "numbers", to: reflect.TypeFor[set.Set[int]](), data: []any{1, 2}, want: set.New(1, 2)},
+		{name: "invalid", to: reflect.TypeFor[set.Set[int]](), data: []any{"a"}, wantErr: true},
+		{name: "not a set", to: reflect.TypeFor[map[string]struct{}](), data: "a,b", want: "a,b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := set.DecodeHook(reflect.TypeOf(tt.data), tt.to, tt.data)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("got error %v, want error: %v", err, tt.wantErr)
+			}
+			if err == nil {
+				checkEqual(t, tt.want, got)
+			}
+		})
+	}
+}
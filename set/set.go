@@ -2,15 +2,16 @@ package set
 
 import (
 	"cmp"
+	"iter"
 	"maps"
 	"slices"
 )
 
 // Set holds a set of unique values
-type Set[T cmp.Ordered] map[T]struct{}
+type Set[T comparable] map[T]struct{}
 
 // New creates a new set containing the optional values
-func New[T cmp.Ordered](values ...T) Set[T] {
+func New[T comparable](values ...T) Set[T] {
 	s := make(Set[T], len(values))
 	for _, value := range values {
 		s[value] = struct{}{}
@@ -18,6 +19,15 @@ func New[T cmp.Ordered](values ...T) Set[T] {
 	return s
 }
 
+// Collect creates a new set containing the values of the iterator
+func Collect[T comparable](seq iter.Seq[T]) Set[T] {
+	s := make(Set[T])
+	for value := range seq {
+		s[value] = struct{}{}
+	}
+	return s
+}
+
 // Add adds value to the set
 func (s Set[T]) Add(value ...T) {
 	for i := range value {
@@ -38,7 +48,7 @@ func (s Set[T]) Contains(value T) bool {
 	return found
 }
 
-// List returns all values present in the set. Order is not guaranteed.  If ListOrdered() if required.
+// List returns all values present in the set. Order is not guaranteed. Use ListOrdered or ListFunc if order is required.
 func (s Set[T]) List() []T {
 	values := make([]T, 0, len(s))
 	for k := range s {
@@ -47,13 +57,75 @@ func (s Set[T]) List() []T {
 	return values
 }
 
+// ListFunc returns all values present in the set, sorted as determined by the cmp function
+func (s Set[T]) ListFunc(cmp func(a, b T) int) []T {
+	values := s.List()
+	slices.SortFunc(values, cmp)
+	return values
+}
+
 // ListOrdered returns all values present in the set, in order
-func (s Set[T]) ListOrdered() []T {
+func ListOrdered[T cmp.Ordered](s Set[T]) []T {
 	values := s.List()
 	slices.Sort(values)
 	return values
 }
 
+// All returns an iterator over all values present in the set. Order is not guaranteed.
+func (s Set[T]) All() iter.Seq[T] {
+	return maps.Keys(s)
+}
+
+// SortedFunc returns an iterator over all values present in the set, sorted as determined by the cmp function
+func (s Set[T]) SortedFunc(cmp func(a, b T) int) iter.Seq[T] {
+	return slices.Values(s.ListFunc(cmp))
+}
+
+// Sorted returns an iterator over all values present in the set, in order
+func Sorted[T cmp.Ordered](s Set[T]) iter.Seq[T] {
+	return slices.Values(ListOrdered(s))
+}
+
+// Filter returns a new set containing the values for which f returns true
+func (s Set[T]) Filter(f func(T) bool) Set[T] {
+	filtered := make(Set[T])
+	for key := range s {
+		if f(key) {
+			filtered[key] = struct{}{}
+		}
+	}
+	return filtered
+}
+
+// Map returns a new set containing the result of f for each value of the set
+func Map[T, U comparable](s Set[T], f func(T) U) Set[U] {
+	mapped := make(Set[U], len(s))
+	for key := range s {
+		mapped[f(key)] = struct{}{}
+	}
+	return mapped
+}
+
+// Any returns true if f returns true for at least one value of the set
+func (s Set[T]) Any(f func(T) bool) bool {
+	for key := range s {
+		if f(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Every returns true if f returns true for all values of the set. Every returns true for an empty set.
+func (s Set[T]) Every(f func(T) bool) bool {
+	for key := range s {
+		if !f(key) {
+			return false
+		}
+	}
+	return true
+}
+
 // Equals returns true if both sets contain the same values
 func (s Set[T]) Equals(other Set[T]) bool {
 	for key := range s {
@@ -77,20 +149,32 @@ func (s Set[T]) Clone() Set[T] {
 	return maps.Clone(s)
 }
 
-// Union returns a new set containing all values from setA and setB
-func Union[T cmp.Ordered](setA, setB Set[T]) Set[T] {
-	union := setA.Clone()
-	for key := range setB {
-		union[key] = struct{}{}
+// Union returns a new set containing all values from all sets
+func Union[T comparable](sets ...Set[T]) Set[T] {
+	var size int
+	for _, s := range sets {
+		size = max(size, len(s))
+	}
+	union := make(Set[T], size)
+	for _, s := range sets {
+		for key := range s {
+			union[key] = struct{}{}
+		}
 	}
 	return union
 }
 
-// Intersection returns a new set containing the common values between setA and setB
-func Intersection[T cmp.Ordered](setA, setB Set[T]) Set[T] {
+// Intersection returns a new set containing the values that exist in all sets
+func Intersection[T comparable](sets ...Set[T]) Set[T] {
 	intersection := make(Set[T])
-	for key := range setA {
-		if _, ok := setB[key]; ok {
+	if len(sets) == 0 {
+		return intersection
+	}
+	// iterate over the smallest set, checking the remaining ones from smallest to largest
+	sets = slices.Clone(sets)
+	slices.SortFunc(sets, func(a, b Set[T]) int { return len(a) - len(b) })
+	for key := range sets[0] {
+		if slices.IndexFunc(sets[1:], func(s Set[T]) bool { return !s.Contains(key) }) == -1 {
 			intersection[key] = struct{}{}
 		}
 	}
@@ -98,7 +182,7 @@ func Intersection[T cmp.Ordered](setA, setB Set[T]) Set[T] {
 }
 
 // Difference returns a new set containing the values from setA that don't exist in setB
-func Difference[T cmp.Ordered](setA, setB Set[T]) Set[T] {
+func Difference[T comparable](setA, setB Set[T]) Set[T] {
 	difference := make(Set[T])
 	for key := range setA {
 		if _, ok := setB[key]; !ok {
@@ -107,3 +191,45 @@ func Difference[T cmp.Ordered](setA, setB Set[T]) Set[T] {
 	}
 	return difference
 }
+
+// SymmetricDifference returns a new set containing the values that exist in either setA or setB, but not in both
+func SymmetricDifference[T comparable](setA, setB Set[T]) Set[T] {
+	difference := Difference(setA, setB)
+	for key := range setB {
+		if _, ok := setA[key]; !ok {
+			difference[key] = struct{}{}
+		}
+	}
+	return difference
+}
+
+// IsSubsetOf returns true if all values of the set exist in other
+func (s Set[T]) IsSubsetOf(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for key := range s {
+		if _, ok := other[key]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSupersetOf returns true if the set contains all values of other
+func (s Set[T]) IsSupersetOf(other Set[T]) bool {
+	return other.IsSubsetOf(s)
+}
+
+// IsDisjoint returns true if the set and other have no values in common
+func (s Set[T]) IsDisjoint(other Set[T]) bool {
+	if len(s) > len(other) {
+		s, other = other, s
+	}
+	for key := range s {
+		if _, ok := other[key]; ok {
+			return false
+		}
+	}
+	return true
+}
@@ -3,6 +3,8 @@ package set_test
 import (
 	"github.com/clambin/go-common/set"
 	"reflect"
+	"slices"
+	"strconv"
 	"testing"
 )
 
@@ -19,7 +21,7 @@ func TestCreate(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			s := set.New(tt.input...).ListOrdered()
+			s := set.ListOrdered(set.New(tt.input...))
 			if !reflect.DeepEqual(s, tt.expected) {
 				t.Errorf("expected: %v, got %v", tt.expected, s)
 			}
@@ -120,11 +122,35 @@ func TestSet_List(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			checkEqual(t, tt.expected, tt.input.ListOrdered())
+			checkEqual(t, tt.expected, set.ListOrdered(tt.input))
 		})
 	}
 }
 
+func TestSet_ListFunc(t *testing.T) {
+	type id struct {
+		name string
+		seq  int
+	}
+	s := set.New(id{name: "b", seq: 1}, id{name: "a", seq: 2}, id{name: "c", seq: 0})
+	checkEqual(t, []id{{name: "c", seq: 0}, {name: "b", seq: 1}, {name: "a", seq: 2}}, s.ListFunc(func(a, b id) int {
+		return a.seq - b.seq
+	}))
+}
+
+func TestSet_Comparable(t *testing.T) {
+	type point struct{ x, y int }
+	a, b, c := &point{x: 1}, &point{x: 1}, &point{y: 1}
+
+	s1 := set.New(a, b)
+	s2 := set.New(b, c)
+	checkEqual(t, 2, len(s1))
+	checkEqual(t, set.New(a, b, c), set.Union(s1, s2))
+	checkEqual(t, set.New(b), set.Intersection(s1, s2))
+	checkEqual(t, set.New(a), set.Difference(s1, s2))
+	checkEqual(t, true, set.New(point{x: 1}).Contains(point{x: 1}))
+}
+
 func TestSet_Equals(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -204,6 +230,19 @@ func TestIntersection(t *testing.T) {
 	}
 }
 
+func TestUnion_Variadic(t *testing.T) {
+	checkEqual(t, set.New[string](), set.Union[string]())
+	checkEqual(t, set.New("A"), set.Union(set.New("A")))
+	checkEqual(t, set.New("A", "B", "C"), set.Union(set.New("A"), set.New("B"), set.New("A", "C")))
+}
+
+func TestIntersection_Variadic(t *testing.T) {
+	checkEqual(t, set.New[string](), set.Intersection[string]())
+	checkEqual(t, set.New("A"), set.Intersection(set.New("A")))
+	checkEqual(t, set.New("B"), set.Intersection(set.New("A", "B", "C"), set.New("B", "C"), set.New("A", "B")))
+	checkEqual(t, set.New[string](), set.Intersection(set.New("A", "B"), set.New[string](), set.New("A", "B")))
+}
+
 func TestDifference(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -226,6 +265,123 @@ func TestDifference(t *testing.T) {
 	}
 }
 
+func TestSymmetricDifference(t *testing.T) {
+	tests := []struct {
+		name     string
+		setA     set.Set[string]
+		setB     set.Set[string]
+		expected set.Set[string]
+	}{
+		{name: "empty", expected: set.New[string]()},
+		{name: "first empty", setB: set.New("A", "B"), expected: set.New("A", "B")},
+		{name: "second empty", setA: set.New("A", "B"), expected: set.New("A", "B")},
+		{name: "overlap", setA: set.New("A", "B"), setB: set.New("B", "C"), expected: set.New("A", "C")},
+		{name: "full overlap", setA: set.New("A", "B"), setB: set.New("A", "B"), expected: set.New[string]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.expected, set.SymmetricDifference(tt.setA, tt.setB))
+		})
+	}
+}
+
+func TestSet_Relations(t *testing.T) {
+	tests := []struct {
+		name       string
+		setA       set.Set[string]
+		setB       set.Set[string]
+		isSubset   bool
+		isSuperset bool
+		isDisjoint bool
+	}{
+		{name: "empty", isSubset: true, isSuperset: true, isDisjoint: true},
+		{name: "first empty", setB: set.New("A"), isSubset: true, isSuperset: false, isDisjoint: true},
+		{name: "equal", setA: set.New("A", "B"), setB: set.New("B", "A"), isSubset: true, isSuperset: true, isDisjoint: false},
+		{name: "subset", setA: set.New("A"), setB: set.New("A", "B"), isSubset: true, isSuperset: false, isDisjoint: false},
+		{name: "superset", setA: set.New("A", "B", "C"), setB: set.New("B"), isSubset: false, isSuperset: true, isDisjoint: false},
+		{name: "overlap", setA: set.New("A", "B"), setB: set.New("B", "C"), isSubset: false, isSuperset: false, isDisjoint: false},
+		{name: "disjoint", setA: set.New("A", "B"), setB: set.New("C"), isSubset: false, isSuperset: false, isDisjoint: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.isSubset, tt.setA.IsSubsetOf(tt.setB))
+			checkEqual(t, tt.isSuperset, tt.setA.IsSupersetOf(tt.setB))
+			checkEqual(t, tt.isDisjoint, tt.setA.IsDisjoint(tt.setB))
+		})
+	}
+}
+
+func TestCollect(t *testing.T) {
+	checkEqual(t, set.New("A", "B"), set.Collect(slices.Values([]string{"A", "B", "A"})))
+	checkEqual(t, set.New[string](), set.Collect(slices.Values([]string{})))
+}
+
+func TestSet_All(t *testing.T) {
+	s := set.New("A", "B", "C")
+	values := slices.Collect(s.All())
+	slices.Sort(values)
+	checkEqual(t, []string{"A", "B", "C"}, values)
+
+	// stop early
+	for range s.All() {
+		break
+	}
+}
+
+func TestSorted(t *testing.T) {
+	s := set.New(3, 1, 2)
+	checkEqual(t, []int{1, 2, 3}, slices.Collect(set.Sorted(s)))
+	checkEqual(t, []int{3, 2, 1}, slices.Collect(s.SortedFunc(func(a, b int) int { return b - a })))
+}
+
+func TestSet_Filter(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    set.Set[int]
+		expected set.Set[int]
+	}{
+		{name: "empty", input: set.New[int](), expected: set.New[int]()},
+		{name: "match", input: set.New(1, 2, 3, 4), expected: set.New(2, 4)},
+		{name: "no match", input: set.New(1, 3), expected: set.New[int]()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.expected, tt.input.Filter(func(i int) bool { return i%2 == 0 }))
+		})
+	}
+}
+
+func TestMap(t *testing.T) {
+	checkEqual(t, set.New("1", "2"), set.Map(set.New(1, 2), strconv.Itoa))
+	// mapping may merge values
+	checkEqual(t, set.New(0, 1), set.Map(set.New(1, 2, 3), func(i int) int { return i % 2 }))
+}
+
+func TestSet_Any_Every(t *testing.T) {
+	tests := []struct {
+		name  string
+		input set.Set[int]
+		any   bool
+		every bool
+	}{
+		{name: "empty", input: set.New[int](), any: false, every: true},
+		{name: "all even", input: set.New(2, 4), any: true, every: true},
+		{name: "some even", input: set.New(1, 2), any: true, every: false},
+		{name: "no even", input: set.New(1, 3), any: false, every: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			even := func(i int) bool { return i%2 == 0 }
+			checkEqual(t, tt.any, tt.input.Any(even))
+			checkEqual(t, tt.every, tt.input.Every(even))
+		})
+	}
+}
+
 func checkEqual[T any](t *testing.T, expected T, got T) {
 	t.Helper()
 	if !reflect.DeepEqual(expected, got) {
@@ -248,3 +404,122 @@ func BenchmarkSet_List(b *testing.B) {
 		_ = bigSet.List()
 	}
 }
+
+// Compared to BenchmarkSet_List, All doesn't allocate.
+func BenchmarkSet_All(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		for range bigSet.All() {
+		}
+	}
+}
+
+func BenchmarkListOrdered(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = set.ListOrdered(bigSet)
+	}
+}
+
+func BenchmarkSorted(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		for range set.Sorted(bigSet) {
+		}
+	}
+}
+
+func BenchmarkSet_Filter(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = bigSet.Filter(func(i int) bool { return i%2 == 0 })
+	}
+}
+
+func BenchmarkSet_Filter_List(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		filtered := make([]int, 0, len(bigSet))
+		for _, i := range bigSet.List() {
+			if i%2 == 0 {
+				filtered = append(filtered, i)
+			}
+		}
+	}
+}
+
+// Compared to mapping the values of List into a new set, Map doesn't allocate the intermediate slice.
+func BenchmarkMap(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = set.Map(bigSet, strconv.Itoa)
+	}
+}
+
+func BenchmarkMap_List(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		mapped := set.New[string]()
+		for _, i := range bigSet.List() {
+			mapped.Add(strconv.Itoa(i))
+		}
+	}
+}
+
+// Any and Every stop at the first (non-)matching value. The benchmarks use a predicate that forces a full scan.
+func BenchmarkSet_Any(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = bigSet.Any(func(i int) bool { return i < 0 })
+	}
+}
+
+func BenchmarkSet_Any_List(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = slices.ContainsFunc(bigSet.List(), func(i int) bool { return i < 0 })
+	}
+}
+
+func BenchmarkSet_Every(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = bigSet.Every(func(i int) bool { return i >= 0 })
+	}
+}
+
+func BenchmarkSet_Every_List(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = !slices.ContainsFunc(bigSet.List(), func(i int) bool { return i < 0 })
+	}
+}
+
+func BenchmarkIntersection(b *testing.B) {
+	bigSet := makeBigSet(10000)
+	smallSet := set.New(1, 10, 100)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = set.Intersection(bigSet, smallSet, bigSet)
+	}
+}
+
+// makeBigSet returns a set containing the integers 0 to size-1
+func makeBigSet(size int) set.Set[int] {
+	s := make(set.Set[int], size)
+	for i := range size {
+		s.Add(i)
+	}
+	return s
+}
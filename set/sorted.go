@@ -0,0 +1,380 @@
+package set
+
+import (
+	"cmp"
+	"iter"
+	"math/rand/v2"
+	"reflect"
+)
+
+const (
+	maxLevel    = 32
+	levelFactor = 4
+)
+
+// SortedSet holds a set of unique values, in sorted order. It is implemented as a skip list, so adding, removing
+// and finding a value is O(log n), and iterating over the values in order doesn't require sorting.
+//
+// The zero value is an empty set, ready to use if T is a number or a string, which are ordered by value. Other types
+// have no natural order: create the set with NewSortedFunc instead, as adding a value to the zero value panics.
+// The set must not be modified while iterating over it.
+type SortedSet[T any] struct {
+	cmp   func(a, b T) int
+	head  *node[T]
+	tail  *node[T]
+	level int
+	len   int
+}
+
+// node is an element of the skip list. span[i] is the number of level-0 nodes between the node and next[i],
+// which allows determining the rank of a value while traversing the list.
+type node[T any] struct {
+	value T
+	next  []*node[T]
+	span  []int
+	prev  *node[T]
+}
+
+// NewSorted creates a new sorted set containing the optional values
+func NewSorted[T cmp.Ordered](values ...T) *SortedSet[T] {
+	return NewSortedFunc(cmp.Compare[T], values...)
+}
+
+// NewSortedFunc creates a new sorted set containing the optional values, ordered as determined by the cmp function.
+// Values for which cmp returns 0 are considered equal.
+func NewSortedFunc[T any](cmp func(a, b T) int, values ...T) *SortedSet[T] {
+	s := SortedSet[T]{cmp: cmp}
+	s.Add(values...)
+	return &s
+}
+
+// init prepares a zero value set for its first value.
+func (s *SortedSet[T]) init() {
+	if s.head != nil {
+		return
+	}
+	if s.cmp == nil {
+		if !ordered[T]() {
+			panic("set: zero value SortedSet of unordered type " + reflect.TypeFor[T]().String() + ": use NewSortedFunc")
+		}
+		s.cmp = compareValues[T]
+	}
+	s.head = &node[T]{next: make([]*node[T], maxLevel), span: make([]int, maxLevel)}
+	s.level = 1
+}
+
+// first returns the node holding the smallest value, or nil if the set is empty.
+func (s *SortedSet[T]) first() *node[T] {
+	if s.head == nil {
+		return nil
+	}
+	return s.head.next[0]
+}
+
+// Add adds value to the set
+func (s *SortedSet[T]) Add(value ...T) {
+	s.init()
+	for i := range value {
+		s.add(value[i])
+	}
+}
+
+func (s *SortedSet[T]) add(value T) {
+	var update [maxLevel]*node[T]
+	var rank [maxLevel]int
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.next[i] != nil && s.cmp(x.next[i].value, value) < 0 {
+			rank[i] += x.span[i]
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	if x.next[0] != nil && s.cmp(x.next[0].value, value) == 0 {
+		return
+	}
+
+	level := randomLevel()
+	for i := s.level; i < level; i++ {
+		rank[i] = 0
+		update[i] = s.head
+		s.head.span[i] = s.len
+	}
+	s.level = max(s.level, level)
+
+	n := &node[T]{value: value, next: make([]*node[T], level), span: make([]int, level)}
+	for i := range level {
+		n.next[i] = update[i].next[i]
+		update[i].next[i] = n
+		n.span[i] = update[i].span[i] - (rank[0] - rank[i])
+		update[i].span[i] = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].span[i]++
+	}
+	if update[0] != s.head {
+		n.prev = update[0]
+	}
+	if n.next[0] != nil {
+		n.next[0].prev = n
+	} else {
+		s.tail = n
+	}
+	s.len++
+}
+
+// Remove deletes value from the set, if present
+func (s *SortedSet[T]) Remove(value ...T) {
+	for i := range value {
+		s.remove(value[i])
+	}
+}
+
+func (s *SortedSet[T]) remove(value T) {
+	if s.head == nil {
+		return
+	}
+	var update [maxLevel]*node[T]
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.cmp(x.next[i].value, value) < 0 {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	x = x.next[0]
+	if x == nil || s.cmp(x.value, value) != 0 {
+		return
+	}
+	for i := range s.level {
+		if update[i].next[i] == x {
+			update[i].span[i] += x.span[i] - 1
+			update[i].next[i] = x.next[i]
+		} else {
+			update[i].span[i]--
+		}
+	}
+	if x.next[0] != nil {
+		x.next[0].prev = x.prev
+	} else {
+		s.tail = x.prev
+	}
+	for s.level > 1 && s.head.next[s.level-1] == nil {
+		s.level--
+	}
+	s.len--
+}
+
+// Contains returns true if the set contains value
+func (s *SortedSet[T]) Contains(value T) bool {
+	n := s.ceiling(value)
+	return n != nil && s.cmp(n.value, value) == 0
+}
+
+// Len returns the number of values in the set
+func (s *SortedSet[T]) Len() int {
+	return s.len
+}
+
+// Min returns the smallest value in the set. If the set is empty, ok is false.
+func (s *SortedSet[T]) Min() (value T, ok bool) {
+	return s.value(s.first())
+}
+
+// Max returns the largest value in the set. If the set is empty, ok is false.
+func (s *SortedSet[T]) Max() (value T, ok bool) {
+	return s.value(s.tail)
+}
+
+// Floor returns the largest value in the set that is less than or equal to value. If no such value exists, ok is false.
+func (s *SortedSet[T]) Floor(value T) (T, bool) {
+	if s.head == nil {
+		return s.value(nil)
+	}
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.cmp(x.next[i].value, value) <= 0 {
+			x = x.next[i]
+		}
+	}
+	if x == s.head {
+		x = nil
+	}
+	return s.value(x)
+}
+
+// Ceiling returns the smallest value in the set that is greater than or equal to value. If no such value exists, ok is false.
+func (s *SortedSet[T]) Ceiling(value T) (T, bool) {
+	return s.value(s.ceiling(value))
+}
+
+func (s *SortedSet[T]) ceiling(value T) *node[T] {
+	if s.head == nil {
+		return nil
+	}
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.cmp(x.next[i].value, value) < 0 {
+			x = x.next[i]
+		}
+	}
+	return x.next[0]
+}
+
+// Rank returns the number of values in the set that are less than value. If the set contains value,
+// this is its (zero-based) position in the set.
+func (s *SortedSet[T]) Rank(value T) int {
+	var rank int
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && s.cmp(x.next[i].value, value) < 0 {
+			rank += x.span[i]
+			x = x.next[i]
+		}
+	}
+	return rank
+}
+
+// At returns the value at the (zero-based) position in the set. If the position is out of range, ok is false.
+func (s *SortedSet[T]) At(position int) (T, bool) {
+	if position < 0 || position >= s.len {
+		return s.value(nil)
+	}
+	var rank int
+	x := s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && rank+x.span[i] <= position+1 {
+			rank += x.span[i]
+			x = x.next[i]
+		}
+	}
+	return s.value(x)
+}
+
+// All returns an iterator over all values in the set, in order
+func (s *SortedSet[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := s.first(); x != nil; x = x.next[0] {
+			if !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over all values in the set, in reverse order
+func (s *SortedSet[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := s.tail; x != nil; x = x.prev {
+			if !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over all values in the set that are greater than or equal to lo and less than or equal to hi, in order
+func (s *SortedSet[T]) Range(lo, hi T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for x := s.ceiling(lo); x != nil && s.cmp(x.value, hi) <= 0; x = x.next[0] {
+			if !yield(x.value) {
+				return
+			}
+		}
+	}
+}
+
+// List returns all values in the set, in order
+func (s *SortedSet[T]) List() []T {
+	values := make([]T, 0, s.len)
+	for value := range s.All() {
+		values = append(values, value)
+	}
+	return values
+}
+
+// Equals returns true if both sets contain the same values
+func (s *SortedSet[T]) Equals(other *SortedSet[T]) bool {
+	if s.len != other.len {
+		return false
+	}
+	for x, y := s.first(), other.first(); x != nil; x, y = x.next[0], y.next[0] {
+		if s.cmp(x.value, y.value) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a copy of the set
+func (s *SortedSet[T]) Clone() *SortedSet[T] {
+	return NewSortedFunc(s.cmp, s.List()...)
+}
+
+// Union returns a new set containing all values from the set and other
+func (s *SortedSet[T]) Union(other *SortedSet[T]) *SortedSet[T] {
+	return s.merge(other, true, true, true)
+}
+
+// Intersection returns a new set containing the common values between the set and other
+func (s *SortedSet[T]) Intersection(other *SortedSet[T]) *SortedSet[T] {
+	return s.merge(other, false, true, false)
+}
+
+// Difference returns a new set containing the values from the set that don't exist in other
+func (s *SortedSet[T]) Difference(other *SortedSet[T]) *SortedSet[T] {
+	return s.merge(other, true, false, false)
+}
+
+// merge walks both sets in order and adds the values that exist only in s, in both sets, and/or only in other.
+func (s *SortedSet[T]) merge(other *SortedSet[T], onlyS, both, onlyOther bool) *SortedSet[T] {
+	result := NewSortedFunc(s.cmp)
+	x, y := s.first(), other.first()
+	for x != nil || y != nil {
+		var c int
+		switch {
+		case x == nil:
+			c = 1
+		case y == nil:
+			c = -1
+		default:
+			c = s.cmp(x.value, y.value)
+		}
+		switch {
+		case c < 0:
+			if onlyS {
+				result.add(x.value)
+			}
+			x = x.next[0]
+		case c > 0:
+			if onlyOther {
+				result.add(y.value)
+			}
+			y = y.next[0]
+		default:
+			if both {
+				result.add(x.value)
+			}
+			x, y = x.next[0], y.next[0]
+		}
+	}
+	return result
+}
+
+func (s *SortedSet[T]) value(n *node[T]) (value T, ok bool) {
+	if n == nil {
+		return value, false
+	}
+	return n.value, true
+}
+
+func randomLevel() int {
+	level := 1
+	for level < maxLevel && rand.IntN(levelFactor) == 0 {
+		level++
+	}
+	return level
+}
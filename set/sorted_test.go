@@ -0,0 +1,224 @@
+package set_test
+
+import (
+	"cmp"
+	"github.com/clambin/go-common/set"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSortedSet(t *testing.T) {
+	s := set.NewSorted(5, 1, 3, 3, 9, 7)
+	checkEqual(t, []int{1, 3, 5, 7, 9}, s.List())
+	checkEqual(t, 5, s.Len())
+	checkEqual(t, true, s.Contains(3))
+	checkEqual(t, false, s.Contains(4))
+
+	s.Add(4, 4)
+	s.Remove(1, 2)
+	checkEqual(t, []int{3, 4, 5, 7, 9}, s.List())
+	checkEqual(t, []int{9, 7, 5, 4, 3}, slices.Collect(s.Backward()))
+}
+
+func TestSortedSet_ZeroValue(t *testing.T) {
+	var s set.SortedSet[int]
+	checkEqual(t, 0, s.Len())
+	checkEqual(t, false, s.Contains(1))
+	_, ok := s.Min()
+	checkEqual(t, false, ok)
+	_, ok = s.Max()
+	checkEqual(t, false, ok)
+	_, ok = s.Floor(1)
+	checkEqual(t, false, ok)
+	_, ok = s.Ceiling(1)
+	checkEqual(t, false, ok)
+	_, ok = s.At(0)
+	checkEqual(t, false, ok)
+	checkEqual(t, 0, s.Rank(1))
+	checkEqual(t, []int{}, s.List())
+	checkEqual(t, []int(nil), slices.Collect(s.Range(0, 10)))
+	checkEqual(t, []int(nil), slices.Collect(s.Backward()))
+	checkEqual(t, true, s.Equals(set.NewSorted[int]()))
+	checkEqual(t, []int{1}, s.Union(set.NewSorted(1)).List())
+	checkEqual(t, []int{}, s.Clone().List())
+	s.Remove(1)
+
+	s.Add(10, -1, 2)
+	checkEqual(t, []int{-1, 2, 10}, s.List())
+	s.Remove(2)
+	checkEqual(t, []int{-1, 10}, s.List())
+}
+
+func TestSortedSet_ZeroValue_Unordered(t *testing.T) {
+	var s set.SortedSet[struct{ id int }]
+	checkEqual(t, 0, s.Len())
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	s.Add(struct{ id int }{id: 1})
+}
+
+func TestSortedSet_Lookup(t *testing.T) {
+	s := set.NewSorted(10, 20, 30)
+	tests := []struct {
+		name    string
+		value   int
+		floor   int
+		floorOK bool
+		ceil    int
+		ceilOK  bool
+		rank    int
+	}{
+		{name: "below", value: 5, ceil: 10, ceilOK: true, rank: 0},
+		{name: "first", value: 10, floor: 10, floorOK: true, ceil: 10, ceilOK: true, rank: 0},
+		{name: "between", value: 25, floor: 20, floorOK: true, ceil: 30, ceilOK: true, rank: 2},
+		{name: "last", value: 30, floor: 30, floorOK: true, ceil: 30, ceilOK: true, rank: 2},
+		{name: "above", value: 35, floor: 30, floorOK: true, rank: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			floor, ok := s.Floor(tt.value)
+			checkEqual(t, tt.floorOK, ok)
+			checkEqual(t, tt.floor, floor)
+			ceil, ok := s.Ceiling(tt.value)
+			checkEqual(t, tt.ceilOK, ok)
+			checkEqual(t, tt.ceil, ceil)
+			checkEqual(t, tt.rank, s.Rank(tt.value))
+		})
+	}
+}
+
+func TestSortedSet_MinMax(t *testing.T) {
+	s := set.NewSorted[string]()
+	_, ok := s.Min()
+	checkEqual(t, false, ok)
+	_, ok = s.Max()
+	checkEqual(t, false, ok)
+
+	s.Add("B", "C", "A")
+	v, ok := s.Min()
+	checkEqual(t, true, ok)
+	checkEqual(t, "A", v)
+	v, ok = s.Max()
+	checkEqual(t, true, ok)
+	checkEqual(t, "C", v)
+}
+
+func TestSortedSet_Range(t *testing.T) {
+	s := set.NewSorted(1, 2, 3, 4, 5, 6)
+	tests := []struct {
+		name     string
+		lo, hi   int
+		expected []int
+	}{
+		{name: "all", lo: 0, hi: 10, expected: []int{1, 2, 3, 4, 5, 6}},
+		{name: "inclusive", lo: 2, hi: 4, expected: []int{2, 3, 4}},
+		{name: "empty", lo: 4, hi: 2},
+		{name: "outside", lo: 7, hi: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkEqual(t, tt.expected, slices.Collect(s.Range(tt.lo, tt.hi)))
+		})
+	}
+}
+
+func TestSortedSet_At(t *testing.T) {
+	s := set.NewSorted("A", "B", "C")
+	for i, expected := range []string{"A", "B", "C"} {
+		v, ok := s.At(i)
+		checkEqual(t, true, ok)
+		checkEqual(t, expected, v)
+	}
+	_, ok := s.At(3)
+	checkEqual(t, false, ok)
+	_, ok = s.At(-1)
+	checkEqual(t, false, ok)
+}
+
+func TestSortedSet_Operations(t *testing.T) {
+	tests := []struct {
+		name         string
+		setA, setB   []int
+		union        []int
+		intersection []int
+		difference   []int
+	}{
+		{name: "empty", union: []int{}, intersection: []int{}, difference: []int{}},
+		{name: "disjoint", setA: []int{1, 3}, setB: []int{2, 4}, union: []int{1, 2, 3, 4}, intersection: []int{}, difference: []int{1, 3}},
+		{name: "overlap", setA: []int{1, 2, 3}, setB: []int{2, 3, 4}, union: []int{1, 2, 3, 4}, intersection: []int{2, 3}, difference: []int{1}},
+		{name: "equal", setA: []int{1, 2}, setB: []int{1, 2}, union: []int{1, 2}, intersection: []int{1, 2}, difference: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := set.NewSorted(tt.setA...), set.NewSorted(tt.setB...)
+			checkEqual(t, tt.union, a.Union(b).List())
+			checkEqual(t, tt.intersection, a.Intersection(b).List())
+			checkEqual(t, tt.difference, a.Difference(b).List())
+			// results match those of Set
+			checkEqual(t, tt.union, set.ListOrdered(set.Union(set.New(tt.setA...), set.New(tt.setB...))))
+		})
+	}
+}
+
+func TestSortedSet_Equals_Clone(t *testing.T) {
+	s := set.NewSorted(1, 2, 3)
+	clone := s.Clone()
+	checkEqual(t, true, s.Equals(clone))
+	clone.Add(4)
+	checkEqual(t, false, s.Equals(clone))
+	clone.Remove(4, 3)
+	checkEqual(t, false, s.Equals(clone))
+}
+
+func TestNewSortedFunc(t *testing.T) {
+	s := set.NewSortedFunc(func(a, b string) int { return cmp.Compare(strings.ToLower(a), strings.ToLower(b)) }, "b", "A", "a", "C")
+	checkEqual(t, []string{"A", "b", "C"}, s.List())
+	checkEqual(t, true, s.Contains("B"))
+}
+
+func TestSortedSet_Random(t *testing.T) {
+	s := set.NewSorted[int]()
+	reference := set.New[int]()
+	for range 10000 {
+		v := rand.IntN(1000)
+		if rand.IntN(3) == 0 {
+			s.Remove(v)
+			reference.Remove(v)
+		} else {
+			s.Add(v)
+			reference.Add(v)
+		}
+	}
+	expected := set.ListOrdered(reference)
+	checkEqual(t, expected, s.List())
+	for i, v := range expected {
+		if rank := s.Rank(v); rank != i {
+			t.Fatalf("Rank(%d): expected %d, got %d", v, i, rank)
+		}
+		if got, _ := s.At(i); got != v {
+			t.Fatalf("At(%d): expected %d, got %d", i, v, got)
+		}
+	}
+}
+
+// Compared to BenchmarkListOrdered, iterating over a SortedSet doesn't require sorting.
+func BenchmarkSortedSet_All(b *testing.B) {
+	const setSize = 10000
+	bigSet := set.NewSorted[int]()
+	for i := range setSize {
+		bigSet.Add(i)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		for range bigSet.All() {
+		}
+	}
+}